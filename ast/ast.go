@@ -0,0 +1,121 @@
+// Package ast defines the S-expression node types produced by the
+// parser and consumed by the evaluator and compiler.
+package ast
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/lexer"
+	"github.com/AOEIUVBPMFDTNL/golisp/number"
+)
+
+// Node is any parsed S-expression.
+type Node interface {
+	String() string
+}
+
+type NumberNode struct {
+	Value number.Number
+	Pos   lexer.Position
+}
+
+func (n *NumberNode) String() string {
+	return n.Value.String()
+}
+
+type SymbolNode struct {
+	Name string
+	Pos  lexer.Position
+}
+
+func (s *SymbolNode) String() string {
+	return s.Name
+}
+
+// StringNode is a string literal, e.g. "hello". Value holds the
+// literal's content after quote stripping and escape interpretation;
+// unlike SymbolNode, it is never looked up as a variable.
+type StringNode struct {
+	Value string
+	Pos   lexer.Position
+}
+
+func (s *StringNode) String() string {
+	return strconv.Quote(s.Value)
+}
+
+// QuoteNode is the AST produced by the `'expr` reader shorthand.
+type QuoteNode struct {
+	Node Node
+	Pos  lexer.Position
+}
+
+func (q *QuoteNode) String() string {
+	return "'" + q.Node.String()
+}
+
+// ListNode is a plain S-expression list, e.g. (f a b) or (a b c).
+type ListNode struct {
+	Elements []Node
+	Pos      lexer.Position
+}
+
+func (l *ListNode) String() string {
+	parts := make([]string, len(l.Elements))
+	for i, e := range l.Elements {
+		parts[i] = e.String()
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// SpecialFormNode is a list whose head symbol names a special form
+// (define, set!, if, lambda, quote, begin, let) rather than a
+// procedure application.
+type SpecialFormNode struct {
+	Form string
+	Args []Node
+	Pos  lexer.Position
+}
+
+func (s *SpecialFormNode) String() string {
+	parts := make([]string, len(s.Args))
+	for i, a := range s.Args {
+		parts[i] = a.String()
+	}
+	return "(" + s.Form + " " + strings.Join(parts, " ") + ")"
+}
+
+// Pos returns the source position of any Node, so callers that only
+// have a Node (not its concrete type) can still report where it came
+// from.
+func Pos(n Node) lexer.Position {
+	switch v := n.(type) {
+	case *NumberNode:
+		return v.Pos
+	case *SymbolNode:
+		return v.Pos
+	case *StringNode:
+		return v.Pos
+	case *QuoteNode:
+		return v.Pos
+	case *ListNode:
+		return v.Pos
+	case *SpecialFormNode:
+		return v.Pos
+	default:
+		return lexer.Position{}
+	}
+}
+
+// SpecialForms is the set of head symbols the parser recognizes as
+// special forms rather than ordinary procedure applications.
+var SpecialForms = map[string]bool{
+	"define": true,
+	"set!":   true,
+	"if":     true,
+	"lambda": true,
+	"quote":  true,
+	"begin":  true,
+	"let":    true,
+}
@@ -0,0 +1,197 @@
+// Command golisp is an interactive REPL for the golisp language: it
+// reads forms from stdin, evaluates them against a persistent
+// environment, and prints results until told to quit.
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/evaluator"
+	"github.com/AOEIUVBPMFDTNL/golisp/lexer"
+	"github.com/AOEIUVBPMFDTNL/golisp/object"
+	"github.com/AOEIUVBPMFDTNL/golisp/parser"
+)
+
+func main() {
+	repl(os.Stdin, os.Stdout)
+}
+
+func repl(in io.Reader, out io.Writer) {
+	env := evaluator.NewGlobalEnv()
+	eval := evaluator.NewEvaluator()
+
+	scanner := bufio.NewScanner(in)
+	var buffer strings.Builder
+
+	printPrompt(out, buffer.Len() > 0)
+	for scanner.Scan() {
+		buffer.WriteString(scanner.Text())
+		buffer.WriteByte('\n')
+
+		source := buffer.String()
+		if strings.TrimSpace(source) == "" {
+			buffer.Reset()
+			printPrompt(out, false)
+			continue
+		}
+
+		switch {
+		case parenDepth(source) > 0:
+			printPrompt(out, true)
+			continue
+		case parenDepth(source) < 0:
+			fmt.Fprintln(out, "Error: unexpected )")
+			buffer.Reset()
+		default:
+			evalLine(strings.TrimSpace(source), out, env, eval)
+			buffer.Reset()
+		}
+		printPrompt(out, false)
+	}
+}
+
+// printError reports err, and, if it's a *lexer.LispError, appends the
+// offending source line with a caret under the column it points at.
+func printError(out io.Writer, source string, err error) {
+	fmt.Fprintln(out, "Error:", err)
+	if le, ok := err.(*lexer.LispError); ok {
+		if snippet := lexer.RenderSnippet(source, le.Pos); snippet != "" {
+			fmt.Fprintln(out, snippet)
+		}
+	}
+}
+
+func printPrompt(out io.Writer, continuation bool) {
+	if continuation {
+		fmt.Fprint(out, "...... ")
+	} else {
+		fmt.Fprint(out, "golisp> ")
+	}
+}
+
+// evalLine handles one complete, balanced chunk of input: either a
+// REPL command (",quit", ",load path", ",env", ",ast expr") or a
+// golisp form to evaluate against env.
+func evalLine(source string, out io.Writer, env *object.Env, eval *evaluator.Evaluator) {
+	if strings.HasPrefix(source, ",") {
+		runCommand(source, out, env, eval)
+		return
+	}
+
+	p, err := parser.NewParser(source)
+	if err != nil {
+		printError(out, source, err)
+		return
+	}
+	node, err := p.Parse()
+	if err != nil {
+		printError(out, source, err)
+		return
+	}
+
+	value, err := eval.Eval(node, env)
+	if err != nil {
+		printError(out, source, err)
+		return
+	}
+	fmt.Fprintln(out, value)
+}
+
+func runCommand(source string, out io.Writer, env *object.Env, eval *evaluator.Evaluator) {
+	name, arg, _ := strings.Cut(source, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case ",quit":
+		os.Exit(0)
+
+	case ",env":
+		bindings := env.Bindings()
+		names := make([]string, 0, len(bindings))
+		for n := range bindings {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		for _, n := range names {
+			fmt.Fprintf(out, "%s = %s\n", n, bindings[n])
+		}
+
+	case ",load":
+		if arg == "" {
+			fmt.Fprintln(out, "Error: ,load requires a path")
+			return
+		}
+		loadFile(arg, out, env, eval)
+
+	case ",ast":
+		if arg == "" {
+			fmt.Fprintln(out, "Error: ,ast requires an expression")
+			return
+		}
+		p, err := parser.NewParser(arg)
+		if err != nil {
+			printError(out, arg, err)
+			return
+		}
+		node, err := p.Parse()
+		if err != nil {
+			printError(out, arg, err)
+			return
+		}
+		fmt.Fprintln(out, node)
+
+	default:
+		fmt.Fprintf(out, "Error: unknown command: %s\n", name)
+	}
+}
+
+func loadFile(path string, out io.Writer, env *object.Env, eval *evaluator.Evaluator) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(out, "Error:", err)
+		return
+	}
+
+	source := string(data)
+	nodes, err := parser.ParseAll(source)
+	if err != nil {
+		printError(out, source, err)
+		return
+	}
+
+	for _, node := range nodes {
+		if _, err := eval.Eval(node, env); err != nil {
+			printError(out, source, err)
+			return
+		}
+	}
+}
+
+// parenDepth counts unmatched "(" in s by reusing the same lexer the
+// parser and REPL commands tokenize with, so it agrees with them about
+// what's a string literal or a ";" comment rather than maintaining its
+// own divergent notion of either. An unterminated string literal scans
+// as still-open input, so it reports a positive depth to keep the REPL
+// prompting for more lines.
+func parenDepth(s string) int {
+	tokens, err := lexer.Scan(s)
+	if err != nil {
+		return 1
+	}
+
+	depth := 0
+	for _, tok := range tokens {
+		switch tok.Kind {
+		case lexer.LParen:
+			depth++
+		case lexer.RParen:
+			depth--
+		}
+	}
+	return depth
+}
@@ -0,0 +1,126 @@
+package compiler
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Instructions is a flat byte-encoded instruction stream: one opcode
+// byte followed by however many bytes its operands take.
+type Instructions []byte
+
+type Opcode byte
+
+const (
+	OpConstant Opcode = iota
+	OpPop
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpEqual
+	OpGreaterThan
+	OpLessThan
+	OpGreaterEq
+	OpLessEq
+	OpJump
+	OpJumpFalse
+	OpGetGlobal
+	OpSetGlobal
+	OpGetLocal
+	OpSetLocal
+	OpGetFree
+	OpSetFree
+	OpGetLocalRef
+	OpGetFreeRef
+	OpCall
+	OpReturn
+	OpClosure
+	OpMakeList
+)
+
+// Definition documents an opcode's mnemonic and the byte width of each
+// of its operands, so Make and the disassembler agree on encoding.
+type Definition struct {
+	Name          string
+	OperandWidths []int
+}
+
+var definitions = map[Opcode]*Definition{
+	OpConstant:    {"OpConstant", []int{2}},
+	OpPop:         {"OpPop", []int{}},
+	OpAdd:         {"OpAdd", []int{}},
+	OpSub:         {"OpSub", []int{}},
+	OpMul:         {"OpMul", []int{}},
+	OpDiv:         {"OpDiv", []int{}},
+	OpEqual:       {"OpEqual", []int{}},
+	OpGreaterThan: {"OpGreaterThan", []int{}},
+	OpLessThan:    {"OpLessThan", []int{}},
+	OpGreaterEq:   {"OpGreaterEq", []int{}},
+	OpLessEq:      {"OpLessEq", []int{}},
+	OpJump:        {"OpJump", []int{2}},
+	OpJumpFalse:   {"OpJumpFalse", []int{2}},
+	OpGetGlobal:   {"OpGetGlobal", []int{2}},
+	OpSetGlobal:   {"OpSetGlobal", []int{2}},
+	// Local/free indices are 2 bytes wide, not 1: a scope can hold more
+	// than 255 locals (e.g. a function body with that many sequential
+	// `define`s), and a 1-byte operand would silently wrap mod 256
+	// instead of erroring, aliasing two distinct locals onto the same
+	// stack slot.
+	OpGetLocal:    {"OpGetLocal", []int{2}},
+	OpSetLocal:    {"OpSetLocal", []int{2}},
+	OpGetFree:     {"OpGetFree", []int{2}},
+	OpSetFree:     {"OpSetFree", []int{2}},
+	OpGetLocalRef: {"OpGetLocalRef", []int{2}},
+	OpGetFreeRef:  {"OpGetFreeRef", []int{2}},
+	OpCall:        {"OpCall", []int{1}},
+	OpReturn:      {"OpReturn", []int{}},
+	OpClosure:     {"OpClosure", []int{2, 1}},
+	OpMakeList:    {"OpMakeList", []int{2}},
+}
+
+func Lookup(op Opcode) (*Definition, error) {
+	def, ok := definitions[op]
+	if !ok {
+		return nil, fmt.Errorf("opcode %d undefined", op)
+	}
+	return def, nil
+}
+
+// Make encodes an opcode and its operands into an instruction.
+func Make(op Opcode, operands ...int) Instructions {
+	def, ok := definitions[op]
+	if !ok {
+		return Instructions{}
+	}
+
+	length := 1
+	for _, w := range def.OperandWidths {
+		length += w
+	}
+
+	instruction := make(Instructions, length)
+	instruction[0] = byte(op)
+
+	offset := 1
+	for i, operand := range operands {
+		width := def.OperandWidths[i]
+		switch width {
+		case 2:
+			binary.BigEndian.PutUint16(instruction[offset:], uint16(operand))
+		case 1:
+			instruction[offset] = byte(operand)
+		}
+		offset += width
+	}
+
+	return instruction
+}
+
+func ReadUint16(ins Instructions) uint16 {
+	return binary.BigEndian.Uint16(ins)
+}
+
+func ReadUint8(ins Instructions) uint8 {
+	return uint8(ins[0])
+}
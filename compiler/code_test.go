@@ -0,0 +1,23 @@
+package compiler
+
+import "testing"
+
+// OpGetLocal/OpSetLocal (and the other local/free-indexed opcodes)
+// must survive an index beyond 255 without truncating: the operand is
+// 2 bytes wide specifically so a function body with more than 255
+// locals doesn't silently alias two distinct slots onto the same byte
+// value mod 256.
+func TestMakeLocalIndexBeyondOneByteDoesNotTruncate(t *testing.T) {
+	ins := Make(OpSetLocal, 300)
+	if got := ReadUint16(ins[1:]); got != 300 {
+		t.Errorf("Make(OpSetLocal, 300) encodes operand as %d, want 300", got)
+	}
+
+	def, err := Lookup(OpSetLocal)
+	if err != nil {
+		t.Fatalf("Lookup(OpSetLocal): unexpected error: %v", err)
+	}
+	if def.OperandWidths[0] != 2 {
+		t.Errorf("OpSetLocal operand width = %d, want 2", def.OperandWidths[0])
+	}
+}
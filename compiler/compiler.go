@@ -0,0 +1,565 @@
+// Package compiler lowers an ast.Node tree into Bytecode that the vm
+// package can execute directly, instead of walking the tree at
+// evaluation time.
+package compiler
+
+import (
+	"fmt"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/ast"
+	"github.com/AOEIUVBPMFDTNL/golisp/lexer"
+	"github.com/AOEIUVBPMFDTNL/golisp/number"
+	"github.com/AOEIUVBPMFDTNL/golisp/object"
+)
+
+// Bytecode is the compiled program: a flat instruction stream, the
+// constant pool it indexes into, and a best-effort map back to source
+// for the top-level form's own instructions.
+type Bytecode struct {
+	Instructions Instructions
+	Constants    []object.Value
+	SourceMap    []object.SourceMapEntry
+}
+
+type emittedInstruction struct {
+	Opcode   Opcode
+	Position int
+}
+
+// compilationScope holds the instruction buffer being built for one
+// lambda body (or the top-level program); entering a lambda pushes a
+// new scope so its Instructions (and SourceMap) can be lifted out as a
+// standalone CompiledFunction.
+type compilationScope struct {
+	instructions        Instructions
+	sourceMap           []object.SourceMapEntry
+	lastInstruction     emittedInstruction
+	previousInstruction emittedInstruction
+}
+
+type Compiler struct {
+	constants    []object.Value
+	symbolTable  *SymbolTable
+	scopes       []compilationScope
+	scopeIndex   int
+	builtinIndex map[string]int
+}
+
+// New returns a Compiler whose global scope already has object's
+// StandardBuiltins defined, at the same names and in the same order the
+// VM pre-populates its globals in, so compileSymbol resolves `+`,
+// `list`, and friends to a global slot even when they're not the head
+// of a directly-called list (e.g. `(define add +)`).
+func New() *Compiler {
+	symbolTable := NewSymbolTable()
+	builtinIndex := make(map[string]int)
+	for _, b := range object.StandardBuiltins() {
+		symbol := symbolTable.Define(b.Name)
+		builtinIndex[b.Name] = symbol.Index
+	}
+	return &Compiler{
+		symbolTable:  symbolTable,
+		scopes:       []compilationScope{{}},
+		builtinIndex: builtinIndex,
+	}
+}
+
+// isBuiltinSlot reports whether name still resolves to the untouched
+// global slot object.StandardBuiltins defined it in, so compileList
+// only takes the dedicated-opcode fast path for `+`, `list`, and
+// friends when nothing has shadowed or redefined them: a lambda
+// parameter or inner `define` resolves to a Local/Free symbol instead,
+// and a top-level `(define + ...)` redefinition moves the name to a
+// new global index, so either case fails this check and falls through
+// to an ordinary call.
+func (c *Compiler) isBuiltinSlot(name string) bool {
+	idx, ok := c.builtinIndex[name]
+	if !ok {
+		return false
+	}
+	symbol, ok := c.symbolTable.Resolve(name)
+	return ok && symbol.Scope == GlobalScope && symbol.Index == idx
+}
+
+// posErrorf builds a LispError tied to pos, the way every other error
+// path in the compiler does, so a caller holding the original source
+// (like the REPL) can always point at where compilation went wrong.
+func posErrorf(pos lexer.Position, format string, args ...interface{}) error {
+	return &lexer.LispError{Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+// CompileProgram compiles a single top-level form and appends the
+// trailing OpPop that moves its value into the VM's "last popped"
+// slot, where Run's caller can retrieve it.
+func (c *Compiler) CompileProgram(node ast.Node) (*Bytecode, error) {
+	if err := c.Compile(node); err != nil {
+		return nil, err
+	}
+	c.emit(OpPop)
+	return c.Bytecode(), nil
+}
+
+func (c *Compiler) Bytecode() *Bytecode {
+	return &Bytecode{
+		Instructions: c.currentInstructions(),
+		Constants:    c.constants,
+		SourceMap:    c.scopes[c.scopeIndex].sourceMap,
+	}
+}
+
+func (c *Compiler) Compile(node ast.Node) error {
+	pos := len(c.currentInstructions())
+
+	var err error
+	switch n := node.(type) {
+	case *ast.NumberNode:
+		idx := c.addConstant(n.Value)
+		c.emit(OpConstant, idx)
+
+	case *ast.StringNode:
+		idx := c.addConstant(object.StringVal(n.Value))
+		c.emit(OpConstant, idx)
+
+	case *ast.SymbolNode:
+		err = c.compileSymbol(n)
+
+	case *ast.QuoteNode:
+		err = c.compileQuoted(n)
+
+	case *ast.ListNode:
+		err = c.compileList(n)
+
+	case *ast.SpecialFormNode:
+		err = c.compileSpecialForm(n)
+
+	default:
+		return posErrorf(ast.Pos(node), "compiler: cannot compile node: %T", node)
+	}
+	if err != nil {
+		return err
+	}
+
+	scope := &c.scopes[c.scopeIndex]
+	scope.sourceMap = append(scope.sourceMap, object.SourceMapEntry{
+		InstrPos: pos,
+		SrcPos:   ast.Pos(node),
+		Source:   node.String(),
+	})
+	return nil
+}
+
+func (c *Compiler) compileSymbol(n *ast.SymbolNode) error {
+	symbol, ok := c.symbolTable.Resolve(n.Name)
+	if !ok {
+		return &lexer.LispError{Pos: n.Pos, Message: fmt.Sprintf("undefined variable: %s", n.Name)}
+	}
+	switch symbol.Scope {
+	case GlobalScope:
+		c.emit(OpGetGlobal, symbol.Index)
+	case LocalScope:
+		c.emit(OpGetLocal, symbol.Index)
+	case FreeScope:
+		c.emit(OpGetFree, symbol.Index)
+	}
+	return nil
+}
+
+func (c *Compiler) compileQuoted(n *ast.QuoteNode) error {
+	value, err := object.FromNode(n.Node)
+	if err != nil {
+		return err
+	}
+	idx := c.addConstant(value)
+	c.emit(OpConstant, idx)
+	return nil
+}
+
+var arithmeticOps = map[string]Opcode{
+	"+": OpAdd,
+	"-": OpSub,
+	"*": OpMul,
+	"/": OpDiv,
+}
+
+var comparisonOps = map[string]Opcode{
+	"<":  OpLessThan,
+	">":  OpGreaterThan,
+	"<=": OpLessEq,
+	">=": OpGreaterEq,
+	"=":  OpEqual,
+}
+
+func (c *Compiler) compileList(n *ast.ListNode) error {
+	if len(n.Elements) == 0 {
+		return posErrorf(n.Pos, "cannot evaluate empty list")
+	}
+
+	head, ok := n.Elements[0].(*ast.SymbolNode)
+	if ok && c.isBuiltinSlot(head.Name) {
+		if op, ok := arithmeticOps[head.Name]; ok {
+			return c.compileArithmetic(op, head.Name, head.Pos, n.Elements[1:])
+		}
+		if op, ok := comparisonOps[head.Name]; ok {
+			return c.compileComparison(op, head.Name, head.Pos, n.Elements[1:])
+		}
+		if head.Name == "list" {
+			return c.compileMakeList(n.Elements[1:])
+		}
+	}
+
+	// Ordinary application: push the callee, then its arguments.
+	if err := c.Compile(n.Elements[0]); err != nil {
+		return err
+	}
+	for _, arg := range n.Elements[1:] {
+		if err := c.Compile(arg); err != nil {
+			return err
+		}
+	}
+	c.emit(OpCall, len(n.Elements)-1)
+	return nil
+}
+
+// compileArithmetic left-folds a variadic (+ a b c ...) into nested
+// binary OpAdd/OpSub/OpMul/OpDiv instructions. A single argument to -
+// or / negates/reciprocates against the operator's identity element,
+// matching Scheme's unary (- x) and (/ x) forms.
+func (c *Compiler) compileArithmetic(op Opcode, name string, pos lexer.Position, args []ast.Node) error {
+	if len(args) == 0 {
+		return posErrorf(pos, "%s: requires at least one argument", name)
+	}
+	if len(args) == 1 && (name == "-" || name == "/") {
+		identity := number.FromInt64(0)
+		if name == "/" {
+			identity = number.FromInt64(1)
+		}
+		c.emit(OpConstant, c.addConstant(identity))
+		if err := c.Compile(args[0]); err != nil {
+			return err
+		}
+		c.emit(op)
+		return nil
+	}
+	if err := c.Compile(args[0]); err != nil {
+		return err
+	}
+	for _, arg := range args[1:] {
+		if err := c.Compile(arg); err != nil {
+			return err
+		}
+		c.emit(op)
+	}
+	return nil
+}
+
+func (c *Compiler) compileComparison(op Opcode, name string, pos lexer.Position, args []ast.Node) error {
+	if len(args) != 2 {
+		return posErrorf(pos, "%s: requires exactly two arguments", name)
+	}
+	if err := c.Compile(args[0]); err != nil {
+		return err
+	}
+	if err := c.Compile(args[1]); err != nil {
+		return err
+	}
+	c.emit(op)
+	return nil
+}
+
+// compileMakeList compiles (list a b c ...) by pushing each argument
+// and then collecting the top len(args) stack slots into a ListVal,
+// mirroring the tree-walking evaluator's "list" builtin.
+func (c *Compiler) compileMakeList(args []ast.Node) error {
+	for _, arg := range args {
+		if err := c.Compile(arg); err != nil {
+			return err
+		}
+	}
+	c.emit(OpMakeList, len(args))
+	return nil
+}
+
+func (c *Compiler) compileSpecialForm(s *ast.SpecialFormNode) error {
+	switch s.Form {
+	case "quote":
+		if len(s.Args) != 1 {
+			return posErrorf(s.Pos, "quote: requires exactly one argument")
+		}
+		value, err := object.FromNode(s.Args[0])
+		if err != nil {
+			return err
+		}
+		c.emit(OpConstant, c.addConstant(value))
+		return nil
+
+	case "define":
+		if len(s.Args) != 2 {
+			return posErrorf(s.Pos, "define: requires a name and a value")
+		}
+		name, ok := s.Args[0].(*ast.SymbolNode)
+		if !ok {
+			return posErrorf(ast.Pos(s.Args[0]), "define: name must be a symbol, got %s", s.Args[0])
+		}
+		// Define the name before compiling the value so a lambda body
+		// referencing its own name (e.g. recursion) resolves correctly.
+		symbol := c.symbolTable.Define(name.Name)
+		if err := c.Compile(s.Args[1]); err != nil {
+			return err
+		}
+		c.emitBinding(symbol)
+		c.emitLoad(symbol)
+		return nil
+
+	case "set!":
+		if len(s.Args) != 2 {
+			return posErrorf(s.Pos, "set!: requires a name and a value")
+		}
+		name, ok := s.Args[0].(*ast.SymbolNode)
+		if !ok {
+			return posErrorf(ast.Pos(s.Args[0]), "set!: name must be a symbol, got %s", s.Args[0])
+		}
+		symbol, ok := c.symbolTable.Resolve(name.Name)
+		if !ok {
+			return &lexer.LispError{Pos: name.Pos, Message: fmt.Sprintf("cannot set! undefined variable: %s", name.Name)}
+		}
+		if err := c.Compile(s.Args[1]); err != nil {
+			return err
+		}
+		c.emitBinding(symbol)
+		c.emitLoad(symbol)
+		return nil
+
+	case "if":
+		return c.compileIf(s)
+
+	case "begin":
+		if len(s.Args) == 0 {
+			return posErrorf(s.Pos, "begin: requires at least one form")
+		}
+		for i, form := range s.Args {
+			if err := c.Compile(form); err != nil {
+				return err
+			}
+			if i < len(s.Args)-1 {
+				c.emit(OpPop)
+			}
+		}
+		return nil
+
+	case "let":
+		return c.compileLet(s)
+
+	case "lambda":
+		return c.compileLambda(s)
+
+	default:
+		return posErrorf(s.Pos, "unknown special form: %s", s.Form)
+	}
+}
+
+func (c *Compiler) emitBinding(symbol Symbol) {
+	switch symbol.Scope {
+	case GlobalScope:
+		c.emit(OpSetGlobal, symbol.Index)
+	case FreeScope:
+		c.emit(OpSetFree, symbol.Index)
+	default:
+		c.emit(OpSetLocal, symbol.Index)
+	}
+}
+
+func (c *Compiler) emitLoad(symbol Symbol) {
+	switch symbol.Scope {
+	case GlobalScope:
+		c.emit(OpGetGlobal, symbol.Index)
+	case FreeScope:
+		c.emit(OpGetFree, symbol.Index)
+	default:
+		c.emit(OpGetLocal, symbol.Index)
+	}
+}
+
+// emitCaptureLoad pushes the Cell backing a free variable itself,
+// rather than the value inside it (emitLoad's OpGetLocal/OpGetFree
+// unwrap that Cell), so OpClosure can capture a shared mutable
+// binding instead of a snapshot of its current value. Resolve never
+// marks a global as free (it returns global symbols directly), so a
+// free symbol's scope is always Local or Free here.
+func (c *Compiler) emitCaptureLoad(symbol Symbol) {
+	switch symbol.Scope {
+	case FreeScope:
+		c.emit(OpGetFreeRef, symbol.Index)
+	default:
+		c.emit(OpGetLocalRef, symbol.Index)
+	}
+}
+
+func (c *Compiler) compileIf(s *ast.SpecialFormNode) error {
+	if len(s.Args) != 2 && len(s.Args) != 3 {
+		return posErrorf(s.Pos, "if: requires a condition, a then branch, and an optional else branch")
+	}
+	if err := c.Compile(s.Args[0]); err != nil {
+		return err
+	}
+
+	jumpFalsePos := c.emit(OpJumpFalse, 9999)
+
+	if err := c.Compile(s.Args[1]); err != nil {
+		return err
+	}
+
+	jumpPos := c.emit(OpJump, 9999)
+
+	c.changeOperand(jumpFalsePos, len(c.currentInstructions()))
+
+	if len(s.Args) == 3 {
+		if err := c.Compile(s.Args[2]); err != nil {
+			return err
+		}
+	} else {
+		c.emit(OpConstant, c.addConstant(number.FromInt64(0)))
+	}
+
+	c.changeOperand(jumpPos, len(c.currentInstructions()))
+	return nil
+}
+
+// compileLet desugars (let ((n v) ...) body...) into an immediately
+// invoked lambda, reusing lambda's local-scope handling.
+func (c *Compiler) compileLet(s *ast.SpecialFormNode) error {
+	if len(s.Args) < 1 {
+		return posErrorf(s.Pos, "let: requires a binding list and a body")
+	}
+	bindings, ok := s.Args[0].(*ast.ListNode)
+	if !ok {
+		return posErrorf(ast.Pos(s.Args[0]), "let: bindings must be a list, got %s", s.Args[0])
+	}
+
+	params := make([]ast.Node, len(bindings.Elements))
+	values := make([]ast.Node, len(bindings.Elements))
+	for i, b := range bindings.Elements {
+		pair, ok := b.(*ast.ListNode)
+		if !ok || len(pair.Elements) != 2 {
+			return posErrorf(ast.Pos(b), "let: each binding must be (name value), got %s", b)
+		}
+		if _, ok := pair.Elements[0].(*ast.SymbolNode); !ok {
+			return posErrorf(ast.Pos(pair.Elements[0]), "let: binding name must be a symbol, got %s", pair.Elements[0])
+		}
+		params[i] = pair.Elements[0]
+		values[i] = pair.Elements[1]
+	}
+
+	lambda := &ast.SpecialFormNode{
+		Form: "lambda",
+		Args: append([]ast.Node{&ast.ListNode{Elements: params}}, s.Args[1:]...),
+	}
+
+	if err := c.compileLambda(lambda); err != nil {
+		return err
+	}
+	for _, v := range values {
+		if err := c.Compile(v); err != nil {
+			return err
+		}
+	}
+	c.emit(OpCall, len(values))
+	return nil
+}
+
+func (c *Compiler) compileLambda(s *ast.SpecialFormNode) error {
+	if len(s.Args) < 2 {
+		return posErrorf(s.Pos, "lambda: requires a parameter list and a body")
+	}
+	paramList, ok := s.Args[0].(*ast.ListNode)
+	if !ok {
+		return posErrorf(ast.Pos(s.Args[0]), "lambda: parameters must be a list, got %s", s.Args[0])
+	}
+
+	c.enterScope()
+
+	params := make([]string, len(paramList.Elements))
+	for i, p := range paramList.Elements {
+		sym, ok := p.(*ast.SymbolNode)
+		if !ok {
+			c.leaveScope()
+			return posErrorf(ast.Pos(p), "lambda: parameter must be a symbol, got %s", p)
+		}
+		params[i] = sym.Name
+		c.symbolTable.Define(sym.Name)
+	}
+
+	body := s.Args[1:]
+	for i, form := range body {
+		if err := c.Compile(form); err != nil {
+			c.leaveScope()
+			return err
+		}
+		if i < len(body)-1 {
+			c.emit(OpPop)
+		}
+	}
+	c.emit(OpReturn)
+
+	freeSymbols := c.symbolTable.FreeSymbols
+	numLocals := c.symbolTable.numDefinitions
+	instructions, sourceMap := c.leaveScope()
+
+	for _, sym := range freeSymbols {
+		c.emitCaptureLoad(sym)
+	}
+
+	compiled := &object.CompiledFunction{
+		Instructions: instructions,
+		NumLocals:    numLocals,
+		NumParams:    len(params),
+		SourceMap:    sourceMap,
+	}
+	idx := c.addConstant(compiled)
+	c.emit(OpClosure, idx, len(freeSymbols))
+	return nil
+}
+
+func (c *Compiler) enterScope() {
+	c.scopes = append(c.scopes, compilationScope{})
+	c.scopeIndex++
+	c.symbolTable = NewEnclosedSymbolTable(c.symbolTable)
+}
+
+func (c *Compiler) leaveScope() (Instructions, []object.SourceMapEntry) {
+	instructions := c.currentInstructions()
+	sourceMap := c.scopes[c.scopeIndex].sourceMap
+	c.scopes = c.scopes[:len(c.scopes)-1]
+	c.scopeIndex--
+	c.symbolTable = c.symbolTable.Outer
+	return instructions, sourceMap
+}
+
+func (c *Compiler) currentInstructions() Instructions {
+	return c.scopes[c.scopeIndex].instructions
+}
+
+func (c *Compiler) addConstant(value object.Value) int {
+	c.constants = append(c.constants, value)
+	return len(c.constants) - 1
+}
+
+func (c *Compiler) emit(op Opcode, operands ...int) int {
+	ins := Make(op, operands...)
+	pos := len(c.currentInstructions())
+	c.scopes[c.scopeIndex].instructions = append(c.currentInstructions(), ins...)
+
+	c.scopes[c.scopeIndex].previousInstruction = c.scopes[c.scopeIndex].lastInstruction
+	c.scopes[c.scopeIndex].lastInstruction = emittedInstruction{Opcode: op, Position: pos}
+	return pos
+}
+
+func (c *Compiler) changeOperand(opPos int, operand int) {
+	op := Opcode(c.currentInstructions()[opPos])
+	newInstruction := Make(op, operand)
+	c.replaceInstruction(opPos, newInstruction)
+}
+
+func (c *Compiler) replaceInstruction(pos int, newInstruction []byte) {
+	ins := c.currentInstructions()
+	copy(ins[pos:], newInstruction)
+}
@@ -0,0 +1,56 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/ast"
+)
+
+// isBuiltinSlot gates compileList's fast path: it must hold for an
+// untouched builtin name, and turn false the moment that name is
+// shadowed by a local or redefined at the top level, the same cases
+// that broke the fast path before it checked the symbol table.
+func TestIsBuiltinSlot(t *testing.T) {
+	c := New()
+	if !c.isBuiltinSlot("+") {
+		t.Error(`isBuiltinSlot("+") = false, want true before any shadowing`)
+	}
+
+	c.enterScope()
+	c.symbolTable.Define("+")
+	if c.isBuiltinSlot("+") {
+		t.Error(`isBuiltinSlot("+") = true, want false once a local shadows it`)
+	}
+	c.leaveScope()
+
+	c.symbolTable.Define("+")
+	if c.isBuiltinSlot("+") {
+		t.Error(`isBuiltinSlot("+") = true, want false once + is redefined globally`)
+	}
+
+	if c.isBuiltinSlot("not-a-builtin") {
+		t.Error(`isBuiltinSlot("not-a-builtin") = true, want false`)
+	}
+}
+
+// compileList only takes the dedicated OpAdd path when + still
+// resolves to its original global slot; otherwise it falls through to
+// an ordinary OpCall, which dispatches to whatever + is bound to.
+func TestCompileListOrdinaryCallWhenShadowed(t *testing.T) {
+	c := New()
+	c.symbolTable.Define("+") // simulate `(define + ...)` at the top level
+
+	node := &ast.ListNode{Elements: []ast.Node{
+		&ast.SymbolNode{Name: "+"},
+		&ast.NumberNode{},
+		&ast.NumberNode{},
+	}}
+	if err := c.compileList(node); err != nil {
+		t.Fatalf("compileList: unexpected error: %v", err)
+	}
+
+	ins := c.currentInstructions()
+	if len(ins) < 2 || Opcode(ins[len(ins)-2]) != OpCall {
+		t.Errorf("compileList emitted %v, want it to end in OpCall", ins)
+	}
+}
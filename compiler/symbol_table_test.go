@@ -0,0 +1,82 @@
+package compiler
+
+import "testing"
+
+func TestSymbolTableDefineGlobal(t *testing.T) {
+	table := NewSymbolTable()
+	a := table.Define("a")
+	b := table.Define("b")
+
+	if a.Scope != GlobalScope || a.Index != 0 {
+		t.Errorf("Define(a) = %+v, want {Scope: GLOBAL, Index: 0}", a)
+	}
+	if b.Scope != GlobalScope || b.Index != 1 {
+		t.Errorf("Define(b) = %+v, want {Scope: GLOBAL, Index: 1}", b)
+	}
+}
+
+// A name defined in an enclosed table shadows the same name in the
+// outer table: Resolve finds the inner definition first.
+func TestSymbolTableShadowing(t *testing.T) {
+	outer := NewSymbolTable()
+	outer.Define("x")
+
+	inner := NewEnclosedSymbolTable(outer)
+	inner.Define("x")
+
+	symbol, ok := inner.Resolve("x")
+	if !ok {
+		t.Fatal("Resolve(x): not found")
+	}
+	if symbol.Scope != LocalScope {
+		t.Errorf("Resolve(x).Scope = %s, want LOCAL", symbol.Scope)
+	}
+}
+
+// A name resolved from an enclosing non-global scope is recorded as a
+// free variable of the scope that had to reach outward for it, so the
+// compiler knows to close over it with OpClosure.
+func TestSymbolTableResolveMarksOuterLocalAsFree(t *testing.T) {
+	global := NewSymbolTable()
+	outer := NewEnclosedSymbolTable(global)
+	outer.Define("x")
+
+	inner := NewEnclosedSymbolTable(outer)
+	symbol, ok := inner.Resolve("x")
+	if !ok {
+		t.Fatal("Resolve(x): not found")
+	}
+	if symbol.Scope != FreeScope {
+		t.Errorf("Resolve(x).Scope = %s, want FREE", symbol.Scope)
+	}
+	if len(inner.FreeSymbols) != 1 || inner.FreeSymbols[0].Name != "x" {
+		t.Errorf("FreeSymbols = %+v, want [{Name: x}]", inner.FreeSymbols)
+	}
+}
+
+// A global is never marked free, however deeply nested the scope that
+// resolves it is: globals live in the VM's flat globals slice, not on
+// a call frame, so there is nothing to capture.
+func TestSymbolTableResolveGlobalNotMarkedFree(t *testing.T) {
+	outer := NewSymbolTable()
+	outer.Define("g")
+
+	inner := NewEnclosedSymbolTable(NewEnclosedSymbolTable(outer))
+	symbol, ok := inner.Resolve("g")
+	if !ok {
+		t.Fatal("Resolve(g): not found")
+	}
+	if symbol.Scope != GlobalScope {
+		t.Errorf("Resolve(g).Scope = %s, want GLOBAL", symbol.Scope)
+	}
+	if len(inner.FreeSymbols) != 0 {
+		t.Errorf("FreeSymbols = %+v, want none", inner.FreeSymbols)
+	}
+}
+
+func TestSymbolTableResolveUndefined(t *testing.T) {
+	table := NewSymbolTable()
+	if _, ok := table.Resolve("missing"); ok {
+		t.Error("Resolve(missing) = ok, want not found")
+	}
+}
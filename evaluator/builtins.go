@@ -0,0 +1,19 @@
+package evaluator
+
+import (
+	"github.com/AOEIUVBPMFDTNL/golisp/object"
+)
+
+// NewGlobalEnv returns a top-level Env with object.StandardBuiltins
+// bound under their names, so applying them goes through the same
+// Env.Lookup + Evaluator.Apply path as user procedures. The compiler
+// binds the same builtins, under the same names, into the VM's
+// globals, so the two backends agree on what `+`, `list`, and friends
+// resolve to.
+func NewGlobalEnv() *object.Env {
+	env := object.NewEnv(nil)
+	for _, b := range object.StandardBuiltins() {
+		env.Define(b.Name, b)
+	}
+	return env
+}
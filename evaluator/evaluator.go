@@ -0,0 +1,250 @@
+// Package evaluator is the tree-walking implementation of golisp: it
+// evaluates an ast.Node directly against an object.Env, without going
+// through the compiler/vm pipeline.
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/ast"
+	"github.com/AOEIUVBPMFDTNL/golisp/lexer"
+	"github.com/AOEIUVBPMFDTNL/golisp/number"
+	"github.com/AOEIUVBPMFDTNL/golisp/object"
+)
+
+// MaxCallDepth bounds how many nested Procedure applications Apply will
+// follow, mirroring the VM's MaxFrames: without it, an ordinary deep
+// (non-tail) recursive program overflows the Go call stack itself,
+// which crashes the process with a fatal error instead of returning one.
+const MaxCallDepth = 1024
+
+// Evaluator walks a Node tree parsed by Parser and reduces it to a
+// Value, resolving symbols and special forms against an Env.
+type Evaluator struct {
+	depth int
+}
+
+func NewEvaluator() *Evaluator {
+	return &Evaluator{}
+}
+
+// posErrorf builds a LispError tied to pos, the way every other error
+// path in the evaluator does, so a caller holding the original source
+// (like the REPL) can always point at where evaluation went wrong.
+func posErrorf(pos lexer.Position, format string, args ...interface{}) error {
+	return &lexer.LispError{Pos: pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *Evaluator) Eval(node ast.Node, env *object.Env) (object.Value, error) {
+	switch n := node.(type) {
+	case *ast.NumberNode:
+		return n.Value, nil
+
+	case *ast.StringNode:
+		return object.StringVal(n.Value), nil
+
+	case *ast.SymbolNode:
+		value, err := env.Lookup(n.Name)
+		if err != nil {
+			return nil, &lexer.LispError{Pos: n.Pos, Message: err.Error()}
+		}
+		return value, nil
+
+	case *ast.QuoteNode:
+		return object.FromNode(n.Node)
+
+	case *ast.ListNode:
+		return e.evalList(n, env)
+
+	case *ast.SpecialFormNode:
+		return e.evalSpecialForm(n, env)
+
+	default:
+		return nil, posErrorf(ast.Pos(node), "cannot evaluate node: %T", node)
+	}
+}
+
+func (e *Evaluator) evalList(n *ast.ListNode, env *object.Env) (object.Value, error) {
+	if len(n.Elements) == 0 {
+		return nil, posErrorf(n.Pos, "cannot evaluate empty list")
+	}
+
+	fn, err := e.Eval(n.Elements[0], env)
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]object.Value, len(n.Elements)-1)
+	for i, a := range n.Elements[1:] {
+		v, err := e.Eval(a, env)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	result, err := e.Apply(fn, args)
+	if err != nil {
+		if _, ok := err.(*lexer.LispError); !ok {
+			err = &lexer.LispError{Pos: n.Pos, Message: err.Error()}
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// Apply calls a Builtin or a user-defined Procedure with already
+// evaluated arguments. It has no source position of its own to attach
+// to an error; evalList, the only caller that has one, attaches the
+// call site's position to whatever plain error comes back.
+func (e *Evaluator) Apply(fn object.Value, args []object.Value) (object.Value, error) {
+	switch f := fn.(type) {
+	case *object.Builtin:
+		return f.Fn(args)
+
+	case *object.Procedure:
+		if len(args) != len(f.Params) {
+			return nil, fmt.Errorf("procedure expects %d argument(s), got %d", len(f.Params), len(args))
+		}
+		if e.depth >= MaxCallDepth {
+			return nil, fmt.Errorf("stack overflow: exceeded max call depth of %d", MaxCallDepth)
+		}
+		callEnv := object.NewEnv(f.Env)
+		for i, param := range f.Params {
+			callEnv.Define(param, args[i])
+		}
+		e.depth++
+		defer func() { e.depth-- }()
+		var result object.Value = number.FromInt64(0)
+		for _, form := range f.Body {
+			var err error
+			result, err = e.Eval(form, callEnv)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+
+	default:
+		return nil, fmt.Errorf("not a procedure: %s", fn)
+	}
+}
+
+func (e *Evaluator) evalSpecialForm(s *ast.SpecialFormNode, env *object.Env) (object.Value, error) {
+	switch s.Form {
+	case "quote":
+		if len(s.Args) != 1 {
+			return nil, posErrorf(s.Pos, "quote: requires exactly one argument")
+		}
+		return object.FromNode(s.Args[0])
+
+	case "define":
+		if len(s.Args) != 2 {
+			return nil, posErrorf(s.Pos, "define: requires a name and a value")
+		}
+		name, ok := s.Args[0].(*ast.SymbolNode)
+		if !ok {
+			return nil, posErrorf(ast.Pos(s.Args[0]), "define: name must be a symbol, got %s", s.Args[0])
+		}
+		value, err := e.Eval(s.Args[1], env)
+		if err != nil {
+			return nil, err
+		}
+		env.Define(name.Name, value)
+		return value, nil
+
+	case "set!":
+		if len(s.Args) != 2 {
+			return nil, posErrorf(s.Pos, "set!: requires a name and a value")
+		}
+		name, ok := s.Args[0].(*ast.SymbolNode)
+		if !ok {
+			return nil, posErrorf(ast.Pos(s.Args[0]), "set!: name must be a symbol, got %s", s.Args[0])
+		}
+		value, err := e.Eval(s.Args[1], env)
+		if err != nil {
+			return nil, err
+		}
+		if err := env.Set(name.Name, value); err != nil {
+			return nil, &lexer.LispError{Pos: name.Pos, Message: err.Error()}
+		}
+		return value, nil
+
+	case "if":
+		if len(s.Args) != 2 && len(s.Args) != 3 {
+			return nil, posErrorf(s.Pos, "if: requires a condition, a then branch, and an optional else branch")
+		}
+		cond, err := e.Eval(s.Args[0], env)
+		if err != nil {
+			return nil, err
+		}
+		if object.IsTruthy(cond) {
+			return e.Eval(s.Args[1], env)
+		}
+		if len(s.Args) == 3 {
+			return e.Eval(s.Args[2], env)
+		}
+		return number.FromInt64(0), nil
+
+	case "begin":
+		if len(s.Args) == 0 {
+			return nil, posErrorf(s.Pos, "begin: requires at least one form")
+		}
+		var result object.Value
+		for _, form := range s.Args {
+			var err error
+			result, err = e.Eval(form, env)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+
+	case "let":
+		if len(s.Args) < 1 {
+			return nil, posErrorf(s.Pos, "let: requires a binding list and a body")
+		}
+		bindings, ok := s.Args[0].(*ast.ListNode)
+		if !ok {
+			return nil, posErrorf(ast.Pos(s.Args[0]), "let: bindings must be a list, got %s", s.Args[0])
+		}
+		letEnv := object.NewEnv(env)
+		for _, b := range bindings.Elements {
+			pair, ok := b.(*ast.ListNode)
+			if !ok || len(pair.Elements) != 2 {
+				return nil, posErrorf(ast.Pos(b), "let: each binding must be (name value), got %s", b)
+			}
+			name, ok := pair.Elements[0].(*ast.SymbolNode)
+			if !ok {
+				return nil, posErrorf(ast.Pos(pair.Elements[0]), "let: binding name must be a symbol, got %s", pair.Elements[0])
+			}
+			value, err := e.Eval(pair.Elements[1], env)
+			if err != nil {
+				return nil, err
+			}
+			letEnv.Define(name.Name, value)
+		}
+		return e.evalSpecialForm(&ast.SpecialFormNode{Form: "begin", Args: s.Args[1:]}, letEnv)
+
+	case "lambda":
+		if len(s.Args) < 2 {
+			return nil, posErrorf(s.Pos, "lambda: requires a parameter list and a body")
+		}
+		paramList, ok := s.Args[0].(*ast.ListNode)
+		if !ok {
+			return nil, posErrorf(ast.Pos(s.Args[0]), "lambda: parameters must be a list, got %s", s.Args[0])
+		}
+		params := make([]string, len(paramList.Elements))
+		for i, p := range paramList.Elements {
+			sym, ok := p.(*ast.SymbolNode)
+			if !ok {
+				return nil, posErrorf(ast.Pos(p), "lambda: parameter must be a symbol, got %s", p)
+			}
+			params[i] = sym.Name
+		}
+		return &object.Procedure{Params: params, Body: s.Args[1:], Env: env}, nil
+
+	default:
+		return nil, posErrorf(s.Pos, "unknown special form: %s", s.Form)
+	}
+}
@@ -0,0 +1,112 @@
+package evaluator
+
+import (
+	"testing"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/parser"
+)
+
+// eval parses and evaluates source against a fresh global Env, the
+// same pairing main.go's REPL uses.
+func eval(t *testing.T, source string) string {
+	t.Helper()
+	p, err := parser.NewParser(source)
+	if err != nil {
+		t.Fatalf("NewParser(%q): unexpected error: %v", source, err)
+	}
+	node, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse(%q): unexpected error: %v", source, err)
+	}
+	value, err := NewEvaluator().Eval(node, NewGlobalEnv())
+	if err != nil {
+		t.Fatalf("Eval(%q): unexpected error: %v", source, err)
+	}
+	return value.String()
+}
+
+// A lambda closes over the Env it was defined in, so a free variable
+// resolves lexically at call time rather than against whatever Env
+// happens to be in scope at the call site.
+func TestEvalClosureCapturesDefiningEnv(t *testing.T) {
+	source := `(begin
+		(define make-adder (lambda (x) (lambda (y) (+ x y))))
+		(define add5 (make-adder 5))
+		(add5 3))`
+	if got := eval(t, source); got != "8" {
+		t.Errorf("eval(%q) = %s, want 8", source, got)
+	}
+}
+
+// Recursion works because `define` binds the name in the same Env the
+// lambda's body runs in, so the lambda can look itself up by name.
+func TestEvalRecursion(t *testing.T) {
+	source := `(begin
+		(define fact (lambda (n) (if (= n 0) 1 (* n (fact (- n 1))))))
+		(fact 5))`
+	if got := eval(t, source); got != "120" {
+		t.Errorf("eval(%q) = %s, want 120", source, got)
+	}
+}
+
+// set! mutates a binding wherever it lives in the Env chain, and that
+// mutation is visible through every closure sharing that Env, not just
+// the one that performed the set!.
+func TestEvalSetMutatesSharedEnv(t *testing.T) {
+	source := `(begin
+		(define make-counter (lambda ()
+			(define n 0)
+			(lambda () (set! n (+ n 1)) n)))
+		(define c (make-counter))
+		(c)
+		(c)
+		(c))`
+	if got := eval(t, source); got != "3" {
+		t.Errorf("eval(%q) = %s, want 3", source, got)
+	}
+}
+
+// Two closures created by separate calls to the same outer lambda get
+// independent Envs: mutating one's captured variable through set!
+// must not affect the other's.
+func TestEvalClosuresAreIndependentPerCall(t *testing.T) {
+	source := `(begin
+		(define make-counter (lambda ()
+			(define n 0)
+			(lambda () (set! n (+ n 1)) n)))
+		(define c1 (make-counter))
+		(define c2 (make-counter))
+		(c1)
+		(c1)
+		(c2)
+		(+ (c1) (c2)))`
+	if got := eval(t, source); got != "5" {
+		t.Errorf("eval(%q) = %s, want 5", source, got)
+	}
+}
+
+// let introduces a fresh scope for its bindings and body, but a
+// binding's own value expression is evaluated in the outer scope, so
+// a let binding cannot refer to another binding defined in the same
+// let (unlike a local `define` inside a lambda body).
+func TestEvalLetBindsInNewScopeWithoutSeeingSiblings(t *testing.T) {
+	source := `(begin
+		(define x 1)
+		(let ((x 2) (y (+ x 10))) (+ x y)))`
+	if got := eval(t, source); got != "13" {
+		t.Errorf("eval(%q) = %s, want 13", source, got)
+	}
+}
+
+// set! inside a let body walks outward past the let's own scope to
+// mutate a binding in an enclosing scope, leaving the let's own
+// bindings alone.
+func TestEvalSetInsideLetMutatesEnclosingScope(t *testing.T) {
+	source := `(begin
+		(define total 0)
+		(let ((step 5)) (set! total (+ total step)))
+		total)`
+	if got := eval(t, source); got != "5" {
+		t.Errorf("eval(%q) = %s, want 5", source, got)
+	}
+}
@@ -0,0 +1,37 @@
+// Package golisp is the library entry point: parsing, evaluation, and
+// compilation live in their own packages, and Run wires them into a
+// single call for callers that just want to execute source. The
+// interactive front end is cmd/golisp.
+package golisp
+
+import (
+	"github.com/AOEIUVBPMFDTNL/golisp/compiler"
+	"github.com/AOEIUVBPMFDTNL/golisp/object"
+	"github.com/AOEIUVBPMFDTNL/golisp/parser"
+	"github.com/AOEIUVBPMFDTNL/golisp/vm"
+)
+
+// Run parses, compiles, and executes source on the bytecode VM,
+// returning the value of its single top-level form.
+func Run(source string) (object.Value, error) {
+	p, err := parser.NewParser(source)
+	if err != nil {
+		return nil, err
+	}
+	node, err := p.Parse()
+	if err != nil {
+		return nil, err
+	}
+
+	bytecode, err := compiler.New().CompileProgram(node)
+	if err != nil {
+		return nil, err
+	}
+
+	machine := vm.New(bytecode)
+	if err := machine.Run(); err != nil {
+		return nil, err
+	}
+
+	return machine.LastPoppedStackElem(), nil
+}
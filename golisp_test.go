@@ -0,0 +1,37 @@
+package golisp
+
+import (
+	"testing"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/evaluator"
+	"github.com/AOEIUVBPMFDTNL/golisp/parser"
+)
+
+const fibSource = "(begin (define fib (lambda (n) (if (< n 2) n (+ (fib (- n 1)) (fib (- n 2)))))) (fib 15))"
+
+func BenchmarkTreeWalk(b *testing.B) {
+	p, err := parser.NewParser(fibSource)
+	if err != nil {
+		b.Fatal(err)
+	}
+	node, err := p.Parse()
+	if err != nil {
+		b.Fatal(err)
+	}
+	eval := evaluator.NewEvaluator()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := eval.Eval(node, evaluator.NewGlobalEnv()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVM(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := Run(fibSource); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package lexer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LispError is a diagnostic tied to a source position. The lexer,
+// parser, evaluator, and compiler all report failures this way instead
+// of a bare Go error, so a caller that holds the original source (like
+// the REPL) can point at exactly where things went wrong.
+type LispError struct {
+	Pos     Position
+	Message string
+}
+
+func (e *LispError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Message)
+}
+
+// RenderSnippet renders the source line e.Pos points into, with a caret
+// under the offending column. It's a separate step from Error, rather
+// than baked into it, because LispError is often constructed deep
+// inside the parser/evaluator/compiler where the original source text
+// isn't in scope; only a caller like the REPL that already holds it can
+// produce the fuller diagnostic.
+func RenderSnippet(source string, pos Position) string {
+	lines := strings.Split(source, "\n")
+	if pos.Line < 1 || pos.Line > len(lines) {
+		return ""
+	}
+	col := pos.Column
+	if col < 1 {
+		col = 1
+	}
+	return lines[pos.Line-1] + "\n" + strings.Repeat(" ", col-1) + "^"
+}
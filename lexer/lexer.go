@@ -0,0 +1,95 @@
+// Package lexer scans golisp source text into a stream of positioned
+// tokens. Pulling this out of the parser lets every downstream stage
+// (parser, evaluator, compiler) report errors as a source position
+// rather than a bare message.
+package lexer
+
+import "regexp"
+
+// numberPattern matches everything number.Parse can turn into a
+// Number: an optionally-signed run of digits, optionally with a decimal
+// point and/or exponent. Anything else lexes as a Symbol, including a
+// lone "+" or "-" used as an operator.
+var numberPattern = regexp.MustCompile(`^[+-]?(\d+\.?\d*|\.\d+)([eE][+-]?\d+)?$`)
+
+// Scan tokenizes source in full and returns an error on the first
+// malformed token (currently just an unterminated string literal).
+func Scan(source string) ([]Token, error) {
+	var tokens []Token
+	runes := []rune(source)
+	line, col, offset := 1, 1, 0
+
+	advance := func(n int) {
+		for i := 0; i < n; i++ {
+			if runes[offset+i] == '\n' {
+				line++
+				col = 1
+			} else {
+				col++
+			}
+		}
+		offset += n
+	}
+
+	for offset < len(runes) {
+		c := runes[offset]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			advance(1)
+
+		case c == ';':
+			for offset < len(runes) && runes[offset] != '\n' {
+				advance(1)
+			}
+
+		case c == '(':
+			tokens = append(tokens, Token{Kind: LParen, Literal: "(", Pos: Position{line, col, offset}})
+			advance(1)
+
+		case c == ')':
+			tokens = append(tokens, Token{Kind: RParen, Literal: ")", Pos: Position{line, col, offset}})
+			advance(1)
+
+		case c == '\'':
+			tokens = append(tokens, Token{Kind: Quote, Literal: "'", Pos: Position{line, col, offset}})
+			advance(1)
+
+		case c == '"':
+			start := offset
+			startPos := Position{line, col, offset}
+			advance(1)
+			for offset < len(runes) && runes[offset] != '"' {
+				if runes[offset] == '\\' && offset+1 < len(runes) {
+					advance(1)
+				}
+				advance(1)
+			}
+			if offset >= len(runes) {
+				return nil, &LispError{Pos: startPos, Message: "unterminated string literal"}
+			}
+			advance(1) // consume closing quote
+			tokens = append(tokens, Token{Kind: String, Literal: string(runes[start:offset]), Pos: startPos})
+
+		default:
+			start := offset
+			startPos := Position{line, col, offset}
+			for offset < len(runes) && !isDelimiter(runes[offset]) {
+				advance(1)
+			}
+			word := string(runes[start:offset])
+			kind := Symbol
+			if numberPattern.MatchString(word) {
+				kind = Number
+			}
+			tokens = append(tokens, Token{Kind: kind, Literal: word, Pos: startPos})
+		}
+	}
+
+	tokens = append(tokens, Token{Kind: EOF, Pos: Position{line, col, offset}})
+	return tokens, nil
+}
+
+func isDelimiter(c rune) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '(' || c == ')' || c == '\'' || c == ';'
+}
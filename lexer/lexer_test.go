@@ -0,0 +1,115 @@
+package lexer
+
+import "testing"
+
+func TestScanKinds(t *testing.T) {
+	tokens, err := Scan(`(+ 1 2.5 "hi" 'x)`)
+	if err != nil {
+		t.Fatalf("Scan: unexpected error: %v", err)
+	}
+
+	want := []Kind{LParen, Symbol, Number, Number, String, Quote, Symbol, RParen, EOF}
+	if len(tokens) != len(want) {
+		t.Fatalf("Scan: got %d tokens, want %d: %v", len(tokens), len(want), tokens)
+	}
+	for i, k := range want {
+		if tokens[i].Kind != k {
+			t.Errorf("token %d: kind = %s, want %s", i, tokens[i].Kind, k)
+		}
+	}
+}
+
+// A lone "+" or "-" lexes as a Symbol, not a Number, since it has no
+// digits; numberPattern requires at least one.
+func TestScanLoneSignIsSymbol(t *testing.T) {
+	tokens, err := Scan("(+ -)")
+	if err != nil {
+		t.Fatalf("Scan: unexpected error: %v", err)
+	}
+	for _, tok := range tokens {
+		if tok.Literal == "+" || tok.Literal == "-" {
+			if tok.Kind != Symbol {
+				t.Errorf("token %q: kind = %s, want Symbol", tok.Literal, tok.Kind)
+			}
+		}
+	}
+}
+
+func TestScanSignedAndExponentNumbers(t *testing.T) {
+	tests := []string{"-7", "+3", "1e3", "1.5e-2", ".5"}
+	for _, src := range tests {
+		tokens, err := Scan(src)
+		if err != nil {
+			t.Fatalf("Scan(%q): unexpected error: %v", src, err)
+		}
+		if tokens[0].Kind != Number {
+			t.Errorf("Scan(%q): first token kind = %s, want Number", src, tokens[0].Kind)
+		}
+	}
+}
+
+// A comment runs to end of line and doesn't affect tokenization of the
+// following line.
+func TestScanComment(t *testing.T) {
+	tokens, err := Scan("1 ; this is a comment\n2")
+	if err != nil {
+		t.Fatalf("Scan: unexpected error: %v", err)
+	}
+	var literals []string
+	for _, tok := range tokens {
+		if tok.Kind != EOF {
+			literals = append(literals, tok.Literal)
+		}
+	}
+	if len(literals) != 2 || literals[0] != "1" || literals[1] != "2" {
+		t.Errorf("Scan: got literals %v, want [1 2]", literals)
+	}
+}
+
+// A String token's Literal keeps the surrounding quotes and any
+// backslash escapes intact; unescaping is the parser's job.
+func TestScanStringLiteralKeepsEscapesRaw(t *testing.T) {
+	tokens, err := Scan(`"a\nb"`)
+	if err != nil {
+		t.Fatalf("Scan: unexpected error: %v", err)
+	}
+	if tokens[0].Kind != String {
+		t.Fatalf("Scan: kind = %s, want String", tokens[0].Kind)
+	}
+	if tokens[0].Literal != `"a\nb"` {
+		t.Errorf("Scan: literal = %q, want %q", tokens[0].Literal, `"a\nb"`)
+	}
+}
+
+func TestScanUnterminatedString(t *testing.T) {
+	_, err := Scan(`"abc`)
+	if err == nil {
+		t.Fatal("Scan: want an error for an unterminated string literal, got nil")
+	}
+	le, ok := err.(*LispError)
+	if !ok {
+		t.Fatalf("Scan: error type = %T, want *LispError", err)
+	}
+	if le.Pos.Line != 1 || le.Pos.Column != 1 {
+		t.Errorf("Scan: error position = %v, want line 1, col 1", le.Pos)
+	}
+}
+
+// Line and column track newlines across tokens, and a token's Pos
+// points at its first rune.
+func TestScanPositionsAcrossLines(t *testing.T) {
+	tokens, err := Scan("(a\n  b)")
+	if err != nil {
+		t.Fatalf("Scan: unexpected error: %v", err)
+	}
+
+	var b Token
+	for _, tok := range tokens {
+		if tok.Literal == "b" {
+			b = tok
+		}
+	}
+	if b.Pos.Line != 2 || b.Pos.Column != 3 {
+		t.Errorf("Scan: \"b\" position = %v, want line 2, col 3", b.Pos)
+	}
+}
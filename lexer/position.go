@@ -0,0 +1,16 @@
+package lexer
+
+import "fmt"
+
+// Position identifies a point in source text: a 1-based line and
+// column for human-readable messages, plus a 0-based rune offset for
+// callers that want to slice the original source.
+type Position struct {
+	Line   int
+	Column int
+	Offset int
+}
+
+func (p Position) String() string {
+	return fmt.Sprintf("line %d, col %d", p.Line, p.Column)
+}
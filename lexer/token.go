@@ -0,0 +1,46 @@
+package lexer
+
+// Kind classifies a Token.
+type Kind int
+
+const (
+	LParen Kind = iota
+	RParen
+	Quote
+	Number
+	String
+	Symbol
+	EOF
+)
+
+func (k Kind) String() string {
+	switch k {
+	case LParen:
+		return "LParen"
+	case RParen:
+		return "RParen"
+	case Quote:
+		return "Quote"
+	case Number:
+		return "Number"
+	case String:
+		return "String"
+	case Symbol:
+		return "Symbol"
+	case EOF:
+		return "EOF"
+	default:
+		return "Unknown"
+	}
+}
+
+// Token is one lexical unit of source text, tagged with the position of
+// its first rune. A String token's Literal keeps the surrounding quotes
+// and any backslash escapes intact; the lexer only scans past them to
+// find the closing quote, leaving the parser to strip the quotes and
+// interpret the escapes.
+type Token struct {
+	Kind    Kind
+	Literal string
+	Pos     Position
+}
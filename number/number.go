@@ -0,0 +1,182 @@
+// Package number implements golisp's arbitrary-precision arithmetic:
+// exact integers and rationals backed by math/big, with a fallback to
+// big.Float once any inexact value enters a computation.
+package number
+
+import (
+	"fmt"
+	"math/big"
+)
+
+type kind int
+
+const (
+	intKind kind = iota
+	ratKind
+	floatKind
+)
+
+// Number is a tagged union over *big.Int, *big.Rat, and *big.Float. The
+// zero value is not valid; construct one with FromInt64, FromFloat64,
+// or Parse.
+type Number struct {
+	kind kind
+	i    *big.Int
+	r    *big.Rat
+	f    *big.Float
+}
+
+func FromInt64(v int64) Number {
+	return Number{kind: intKind, i: big.NewInt(v)}
+}
+
+func FromBigInt(v *big.Int) Number {
+	return Number{kind: intKind, i: v}
+}
+
+func FromFloat64(v float64) Number {
+	return Number{kind: floatKind, f: big.NewFloat(v)}
+}
+
+// Parse chooses the narrowest representation for a numeric literal
+// token: digits alone (optionally signed) parse as an exact integer;
+// anything with a decimal point or exponent parses as a float. It
+// reports false if token isn't a valid number at all.
+func Parse(token string) (Number, bool) {
+	if i, ok := new(big.Int).SetString(token, 10); ok {
+		return Number{kind: intKind, i: i}, true
+	}
+	if f, ok := new(big.Float).SetString(token); ok {
+		return Number{kind: floatKind, f: f}, true
+	}
+	return Number{}, false
+}
+
+func (n Number) String() string {
+	switch n.kind {
+	case intKind:
+		return n.i.String()
+	case ratKind:
+		return n.r.RatString()
+	case floatKind:
+		return n.f.Text('g', -1)
+	default:
+		return "<invalid number>"
+	}
+}
+
+func (n Number) IsZero() bool {
+	switch n.kind {
+	case intKind:
+		return n.i.Sign() == 0
+	case ratKind:
+		return n.r.Sign() == 0
+	case floatKind:
+		return n.f.Sign() == 0
+	default:
+		return true
+	}
+}
+
+func (n Number) toRat() *big.Rat {
+	switch n.kind {
+	case intKind:
+		return new(big.Rat).SetInt(n.i)
+	case ratKind:
+		return n.r
+	case floatKind:
+		r, _ := n.f.Rat(nil)
+		return r
+	default:
+		return new(big.Rat)
+	}
+}
+
+func (n Number) toFloat() *big.Float {
+	switch n.kind {
+	case intKind:
+		return new(big.Float).SetInt(n.i)
+	case ratKind:
+		f := new(big.Float)
+		f.SetRat(n.r)
+		return f
+	case floatKind:
+		return n.f
+	default:
+		return new(big.Float)
+	}
+}
+
+// fromRat demotes an exact-denominator-1 rational back to an integer,
+// so (+ 1/2 1/2) reports as 1, not 1/1.
+func fromRat(r *big.Rat) Number {
+	if r.IsInt() {
+		return Number{kind: intKind, i: new(big.Int).Set(r.Num())}
+	}
+	return Number{kind: ratKind, r: r}
+}
+
+func Add(a, b Number) Number {
+	if a.kind == floatKind || b.kind == floatKind {
+		return Number{kind: floatKind, f: new(big.Float).Add(a.toFloat(), b.toFloat())}
+	}
+	if a.kind == ratKind || b.kind == ratKind {
+		return fromRat(new(big.Rat).Add(a.toRat(), b.toRat()))
+	}
+	return Number{kind: intKind, i: new(big.Int).Add(a.i, b.i)}
+}
+
+func Sub(a, b Number) Number {
+	if a.kind == floatKind || b.kind == floatKind {
+		return Number{kind: floatKind, f: new(big.Float).Sub(a.toFloat(), b.toFloat())}
+	}
+	if a.kind == ratKind || b.kind == ratKind {
+		return fromRat(new(big.Rat).Sub(a.toRat(), b.toRat()))
+	}
+	return Number{kind: intKind, i: new(big.Int).Sub(a.i, b.i)}
+}
+
+func Mul(a, b Number) Number {
+	if a.kind == floatKind || b.kind == floatKind {
+		return Number{kind: floatKind, f: new(big.Float).Mul(a.toFloat(), b.toFloat())}
+	}
+	if a.kind == ratKind || b.kind == ratKind {
+		return fromRat(new(big.Rat).Mul(a.toRat(), b.toRat()))
+	}
+	return Number{kind: intKind, i: new(big.Int).Mul(a.i, b.i)}
+}
+
+// Div returns an error for division by zero rather than a float
+// Inf/NaN, since exact integers and rationals have no such value.
+func Div(a, b Number) (Number, error) {
+	if b.IsZero() {
+		return Number{}, fmt.Errorf("division by zero")
+	}
+	if a.kind == floatKind || b.kind == floatKind {
+		return Number{kind: floatKind, f: new(big.Float).Quo(a.toFloat(), b.toFloat())}, nil
+	}
+	// Integer division that doesn't reduce cleanly yields an exact
+	// rational instead of losing precision, e.g. (/ 1 3) => 1/3.
+	return fromRat(new(big.Rat).Quo(a.toRat(), b.toRat())), nil
+}
+
+func Neg(a Number) Number {
+	switch a.kind {
+	case intKind:
+		return Number{kind: intKind, i: new(big.Int).Neg(a.i)}
+	case ratKind:
+		return Number{kind: ratKind, r: new(big.Rat).Neg(a.r)}
+	default:
+		return Number{kind: floatKind, f: new(big.Float).Neg(a.f)}
+	}
+}
+
+// Cmp returns -1, 0, or 1 as a is less than, equal to, or greater than
+// b, comparing exactly when both sides are integers or rationals and
+// falling back to big.Float comparison once either side is inexact.
+func Cmp(a, b Number) int {
+	if a.kind == floatKind || b.kind == floatKind {
+		return a.toFloat().Cmp(b.toFloat())
+	}
+	return a.toRat().Cmp(b.toRat())
+}
@@ -0,0 +1,139 @@
+package number
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		token string
+		want  string
+		ok    bool
+	}{
+		{"42", "42", true},
+		{"-7", "-7", true},
+		{"3.14", "3.14", true},
+		{"1e3", "1000", true},
+		{"not-a-number", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		n, ok := Parse(tt.token)
+		if ok != tt.ok {
+			t.Errorf("Parse(%q) ok = %v, want %v", tt.token, ok, tt.ok)
+			continue
+		}
+		if ok && n.String() != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.token, n.String(), tt.want)
+		}
+	}
+}
+
+// Add, Sub, and Mul stay in the narrowest representation possible:
+// int+int stays int, and only introducing a rational or a float widens
+// the result.
+func TestArithmeticKindPromotion(t *testing.T) {
+	i := FromInt64(2)
+	r := fromRat(big.NewRat(1, 2))
+	f := FromFloat64(1.5)
+
+	if got := Add(i, i); got.kind != intKind {
+		t.Errorf("int + int: kind = %v, want intKind", got.kind)
+	}
+	if got := Add(i, r); got.kind != ratKind {
+		t.Errorf("int + rat: kind = %v, want ratKind", got.kind)
+	}
+	if got := Add(i, f); got.kind != floatKind {
+		t.Errorf("int + float: kind = %v, want floatKind", got.kind)
+	}
+	if got := Add(r, f); got.kind != floatKind {
+		t.Errorf("rat + float: kind = %v, want floatKind", got.kind)
+	}
+}
+
+// fromRat demotes an exact-denominator-1 rational back to an integer,
+// so adding two halves reports as an int rather than "1/1".
+func TestFromRatDemotesWholeNumbers(t *testing.T) {
+	half := fromRat(big.NewRat(1, 2))
+	sum := Add(half, half)
+	if sum.kind != intKind {
+		t.Fatalf("1/2 + 1/2: kind = %v, want intKind", sum.kind)
+	}
+	if sum.String() != "1" {
+		t.Errorf("1/2 + 1/2 = %q, want \"1\"", sum.String())
+	}
+}
+
+func TestDivByZero(t *testing.T) {
+	if _, err := Div(FromInt64(1), FromInt64(0)); err == nil {
+		t.Error("Div(1, 0): want an error, got nil")
+	}
+}
+
+// Integer division that doesn't reduce cleanly yields an exact
+// rational rather than losing precision.
+func TestDivProducesExactRational(t *testing.T) {
+	got, err := Div(FromInt64(1), FromInt64(3))
+	if err != nil {
+		t.Fatalf("Div(1, 3): unexpected error: %v", err)
+	}
+	if got.kind != ratKind {
+		t.Fatalf("Div(1, 3): kind = %v, want ratKind", got.kind)
+	}
+	if got.String() != "1/3" {
+		t.Errorf("Div(1, 3) = %q, want \"1/3\"", got.String())
+	}
+}
+
+func TestDivWithFloatOperandYieldsFloat(t *testing.T) {
+	got, err := Div(FromInt64(1), FromFloat64(4))
+	if err != nil {
+		t.Fatalf("Div(1, 4.0): unexpected error: %v", err)
+	}
+	if got.kind != floatKind {
+		t.Fatalf("Div(1, 4.0): kind = %v, want floatKind", got.kind)
+	}
+	if got.String() != "0.25" {
+		t.Errorf("Div(1, 4.0) = %q, want \"0.25\"", got.String())
+	}
+}
+
+func TestCmp(t *testing.T) {
+	tests := []struct {
+		a, b Number
+		want int
+	}{
+		{FromInt64(1), FromInt64(2), -1},
+		{FromInt64(2), FromInt64(2), 0},
+		{FromInt64(3), FromInt64(2), 1},
+		{fromRat(big.NewRat(1, 2)), FromFloat64(0.5), 0},
+		{FromInt64(1), fromRat(big.NewRat(3, 2)), -1},
+	}
+	for _, tt := range tests {
+		if got := Cmp(tt.a, tt.b); got != tt.want {
+			t.Errorf("Cmp(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNeg(t *testing.T) {
+	if got := Neg(FromInt64(5)).String(); got != "-5" {
+		t.Errorf("Neg(5) = %q, want \"-5\"", got)
+	}
+	if got := Neg(fromRat(big.NewRat(1, 2))).String(); got != "-1/2" {
+		t.Errorf("Neg(1/2) = %q, want \"-1/2\"", got)
+	}
+}
+
+func TestIsZero(t *testing.T) {
+	if !FromInt64(0).IsZero() {
+		t.Error("FromInt64(0).IsZero() = false, want true")
+	}
+	if FromInt64(1).IsZero() {
+		t.Error("FromInt64(1).IsZero() = true, want false")
+	}
+	if !fromRat(big.NewRat(0, 1)).IsZero() {
+		t.Error("fromRat(0/1).IsZero() = false, want true")
+	}
+}
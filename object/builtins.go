@@ -0,0 +1,141 @@
+package object
+
+import (
+	"fmt"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/number"
+)
+
+// StandardBuiltins returns the arithmetic, comparison, and list
+// procedures every golisp global environment starts with, in a fixed
+// order. Both the tree-walking evaluator and the compiler/VM pipeline
+// bind these under the same names at the same global slots, so a
+// program that passes `+` or `list` around as a first-class value
+// behaves the same on either backend.
+func StandardBuiltins() []*Builtin {
+	return []*Builtin{
+		{Name: "+", Fn: builtinAdd},
+		{Name: "-", Fn: builtinSub},
+		{Name: "*", Fn: builtinMul},
+		{Name: "/", Fn: builtinDiv},
+		{Name: "<", Fn: builtinCompare("<")},
+		{Name: ">", Fn: builtinCompare(">")},
+		{Name: "<=", Fn: builtinCompare("<=")},
+		{Name: ">=", Fn: builtinCompare(">=")},
+		{Name: "=", Fn: builtinCompare("=")},
+		{Name: "list", Fn: builtinList},
+	}
+}
+
+func numberArgs(args []Value) ([]number.Number, error) {
+	nums := make([]number.Number, len(args))
+	for i, a := range args {
+		n, ok := a.(NumberVal)
+		if !ok {
+			return nil, fmt.Errorf("expected a number, got %s", a)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
+
+func builtinAdd(args []Value) (Value, error) {
+	nums, err := numberArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	sum := number.FromInt64(0)
+	for _, n := range nums {
+		sum = number.Add(sum, n)
+	}
+	return sum, nil
+}
+
+func builtinMul(args []Value) (Value, error) {
+	nums, err := numberArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	product := number.FromInt64(1)
+	for _, n := range nums {
+		product = number.Mul(product, n)
+	}
+	return product, nil
+}
+
+func builtinSub(args []Value) (Value, error) {
+	nums, err := numberArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(nums) == 0 {
+		return nil, fmt.Errorf("-: requires at least one argument")
+	}
+	if len(nums) == 1 {
+		return number.Neg(nums[0]), nil
+	}
+	result := nums[0]
+	for _, n := range nums[1:] {
+		result = number.Sub(result, n)
+	}
+	return result, nil
+}
+
+func builtinDiv(args []Value) (Value, error) {
+	nums, err := numberArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(nums) == 0 {
+		return nil, fmt.Errorf("/: requires at least one argument")
+	}
+	if len(nums) == 1 {
+		return number.Div(number.FromInt64(1), nums[0])
+	}
+	result := nums[0]
+	for _, n := range nums[1:] {
+		result, err = number.Div(result, n)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// builtinList collects its already-evaluated arguments into a ListVal,
+// the same construction OpMakeList performs on the VM.
+func builtinList(args []Value) (Value, error) {
+	list := make(ListVal, len(args))
+	copy(list, args)
+	return list, nil
+}
+
+func builtinCompare(op string) func([]Value) (Value, error) {
+	return func(args []Value) (Value, error) {
+		nums, err := numberArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		if len(nums) != 2 {
+			return nil, fmt.Errorf("%s: requires exactly two arguments", op)
+		}
+		cmp := number.Cmp(nums[0], nums[1])
+		var result bool
+		switch op {
+		case "<":
+			result = cmp < 0
+		case ">":
+			result = cmp > 0
+		case "<=":
+			result = cmp <= 0
+		case ">=":
+			result = cmp >= 0
+		case "=":
+			result = cmp == 0
+		}
+		if result {
+			return number.FromInt64(1), nil
+		}
+		return number.FromInt64(0), nil
+	}
+}
@@ -0,0 +1,57 @@
+package object
+
+import "fmt"
+
+// Env is a lexical scope: a set of local bindings plus a link to the
+// enclosing scope. Lookups and set! walk outward through parent until a
+// binding is found; define always creates or overwrites in the current
+// scope.
+type Env struct {
+	vars   map[string]Value
+	parent *Env
+}
+
+func NewEnv(parent *Env) *Env {
+	return &Env{
+		vars:   make(map[string]Value),
+		parent: parent,
+	}
+}
+
+func (e *Env) Lookup(name string) (Value, error) {
+	if v, ok := e.vars[name]; ok {
+		return v, nil
+	}
+	if e.parent != nil {
+		return e.parent.Lookup(name)
+	}
+	return nil, fmt.Errorf("undefined variable: %s", name)
+}
+
+func (e *Env) Define(name string, value Value) {
+	e.vars[name] = value
+}
+
+// Bindings returns a copy of the names defined directly in this scope,
+// for tooling like a REPL's ,env command; it does not include parent
+// scopes.
+func (e *Env) Bindings() map[string]Value {
+	bindings := make(map[string]Value, len(e.vars))
+	for name, value := range e.vars {
+		bindings[name] = value
+	}
+	return bindings
+}
+
+// Set implements set!: it assigns to the nearest enclosing scope that
+// already defines name, and errors if none does.
+func (e *Env) Set(name string, value Value) error {
+	if _, ok := e.vars[name]; ok {
+		e.vars[name] = value
+		return nil
+	}
+	if e.parent != nil {
+		return e.parent.Set(name, value)
+	}
+	return fmt.Errorf("cannot set! undefined variable: %s", name)
+}
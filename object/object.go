@@ -0,0 +1,187 @@
+// Package object defines the runtime values the evaluator and the VM
+// produce: Value itself, plus the lexical environment values are bound
+// in.
+package object
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/ast"
+	"github.com/AOEIUVBPMFDTNL/golisp/lexer"
+	"github.com/AOEIUVBPMFDTNL/golisp/number"
+)
+
+// Value is the result of evaluating a Node: a number, a symbol, a list,
+// or something callable (a user-defined procedure or a builtin).
+type Value interface {
+	String() string
+}
+
+// NumberVal is an alias, not a wrapper, so a number.Number produced by
+// the parser or arithmetic package can be used as a Value directly.
+type NumberVal = number.Number
+
+type SymbolVal string
+
+func (s SymbolVal) String() string {
+	return string(s)
+}
+
+// StringVal is a string literal's runtime value. It prints quoted, so
+// it stays distinguishable from a SymbolVal at the REPL.
+type StringVal string
+
+func (s StringVal) String() string {
+	return strconv.Quote(string(s))
+}
+
+type ListVal []Value
+
+func (l ListVal) String() string {
+	parts := make([]string, len(l))
+	for i, v := range l {
+		parts[i] = v.String()
+	}
+	return "(" + strings.Join(parts, " ") + ")"
+}
+
+// Procedure is a closure created by the tree-walking evaluator when it
+// evaluates a lambda: it remembers the environment it was defined in so
+// free variables resolve lexically rather than dynamically at call time.
+type Procedure struct {
+	Params []string
+	Body   []ast.Node
+	Env    *Env
+}
+
+func (p *Procedure) String() string {
+	return fmt.Sprintf("#<procedure (%s)>", strings.Join(p.Params, " "))
+}
+
+// Builtin wraps a native Go function as a callable Value.
+type Builtin struct {
+	Name string
+	Fn   func(args []Value) (Value, error)
+}
+
+func (b *Builtin) String() string {
+	return fmt.Sprintf("#<builtin %s>", b.Name)
+}
+
+// SourceMapEntry records that the instruction at InstrPos was compiled
+// from the form at SrcPos, so the VM can recover a source position for
+// a runtime error from nothing but the instruction pointer it was at.
+type SourceMapEntry struct {
+	InstrPos int
+	SrcPos   lexer.Position
+	Source   string
+}
+
+// CompiledFunction is the bytecode analogue of a lambda body: the
+// compiler emits one per `lambda` form. It carries no captured state
+// itself; a Closure pairs it with the free variables captured at the
+// point OpClosure ran. SourceMap is sorted by InstrPos, covering only
+// this function's own instructions.
+type CompiledFunction struct {
+	Instructions []byte
+	NumLocals    int
+	NumParams    int
+	SourceMap    []SourceMapEntry
+}
+
+func (c *CompiledFunction) String() string {
+	return "#<compiled-procedure>"
+}
+
+// Closure is a CompiledFunction plus the free variables it captured
+// when OpClosure created it. Free holds Cells, not raw Values, so a
+// variable shared between an enclosing scope and the closure stays a
+// single mutable binding on both sides: set! on either end is visible
+// to the other, the same way the tree-walking evaluator's shared *Env
+// makes set! visible through a closure's captured environment.
+type Closure struct {
+	Fn   *CompiledFunction
+	Free []*Cell
+}
+
+func (c *Closure) String() string {
+	return "#<closure>"
+}
+
+// Cell is a mutable reference to a local variable's value. The VM
+// stores every local (and, through Closure.Free, every captured free
+// variable) behind a Cell rather than as a raw Value on the stack, so
+// OpClosure can capture a shared binding instead of a point-in-time
+// snapshot.
+type Cell struct {
+	Value Value
+}
+
+func (c *Cell) String() string {
+	if c.Value == nil {
+		return "#<unbound>"
+	}
+	return c.Value.String()
+}
+
+// FromNode converts a quoted AST node into data, the way `quote`
+// suppresses evaluation. A SpecialFormNode is reconstructed as a plain
+// list headed by its keyword, since quoting should hide the fact that
+// the parser recognizes that keyword at all: '(if a b) is data, not a
+// conditional.
+func FromNode(node ast.Node) (Value, error) {
+	switch n := node.(type) {
+	case *ast.NumberNode:
+		return n.Value, nil
+
+	case *ast.SymbolNode:
+		return SymbolVal(n.Name), nil
+
+	case *ast.StringNode:
+		return StringVal(n.Value), nil
+
+	case *ast.QuoteNode:
+		inner, err := FromNode(n.Node)
+		if err != nil {
+			return nil, err
+		}
+		return ListVal{SymbolVal("quote"), inner}, nil
+
+	case *ast.ListNode:
+		elems := make(ListVal, len(n.Elements))
+		for i, e := range n.Elements {
+			v, err := FromNode(e)
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = v
+		}
+		return elems, nil
+
+	case *ast.SpecialFormNode:
+		elems := make(ListVal, len(n.Args)+1)
+		elems[0] = SymbolVal(n.Form)
+		for i, a := range n.Args {
+			v, err := FromNode(a)
+			if err != nil {
+				return nil, err
+			}
+			elems[i+1] = v
+		}
+		return elems, nil
+
+	default:
+		return nil, fmt.Errorf("cannot quote node: %T", node)
+	}
+}
+
+// IsTruthy follows the common Lisp convention that everything is
+// truthy except the number zero.
+func IsTruthy(v Value) bool {
+	if n, ok := v.(NumberVal); ok {
+		return !n.IsZero()
+	}
+	return true
+}
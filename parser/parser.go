@@ -0,0 +1,176 @@
+// Package parser turns golisp source text into an ast.Node tree.
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/ast"
+	"github.com/AOEIUVBPMFDTNL/golisp/lexer"
+	"github.com/AOEIUVBPMFDTNL/golisp/number"
+)
+
+type Parser struct {
+	tokens []lexer.Token
+	pos    int
+}
+
+func NewParser(input string) (*Parser, error) {
+	tokens, err := lexer.Scan(input)
+	if err != nil {
+		return nil, err
+	}
+	return &Parser{tokens: tokens}, nil
+}
+
+func (p *Parser) current() lexer.Token {
+	return p.tokens[p.pos]
+}
+
+// ParseAll reads every top-level form in source, e.g. the contents of
+// a file containing several definitions in sequence.
+func ParseAll(source string) ([]ast.Node, error) {
+	p, err := NewParser(source)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []ast.Node
+	for p.current().Kind != lexer.EOF {
+		node, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// Parse reads a single top-level form and errors if input remains.
+func (p *Parser) Parse() (ast.Node, error) {
+	node, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok := p.current(); tok.Kind != lexer.EOF {
+		return nil, &lexer.LispError{Pos: tok.Pos, Message: fmt.Sprintf("unexpected token: %s", tok.Literal)}
+	}
+
+	return node, nil
+}
+
+func (p *Parser) parseExpr() (ast.Node, error) {
+	tok := p.current()
+
+	switch tok.Kind {
+	case lexer.EOF:
+		return nil, &lexer.LispError{Pos: tok.Pos, Message: "unexpected end of input"}
+
+	case lexer.Quote:
+		p.pos++
+		quoted, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		return &ast.QuoteNode{Node: quoted, Pos: tok.Pos}, nil
+
+	case lexer.LParen:
+		return p.parseList()
+
+	case lexer.RParen:
+		return nil, &lexer.LispError{Pos: tok.Pos, Message: "unexpected token: )"}
+
+	default:
+		return p.parseAtom()
+	}
+}
+
+func (p *Parser) parseList() (ast.Node, error) {
+	start := p.current().Pos
+	p.pos++ // consume "("
+
+	var elements []ast.Node
+	for {
+		tok := p.current()
+		if tok.Kind == lexer.EOF {
+			return nil, &lexer.LispError{Pos: tok.Pos, Message: "missing closing parenthesis"}
+		}
+		if tok.Kind == lexer.RParen {
+			p.pos++
+			break
+		}
+
+		elem, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		elements = append(elements, elem)
+	}
+
+	if len(elements) > 0 {
+		if head, ok := elements[0].(*ast.SymbolNode); ok && ast.SpecialForms[head.Name] {
+			return &ast.SpecialFormNode{Form: head.Name, Args: elements[1:], Pos: start}, nil
+		}
+	}
+
+	return &ast.ListNode{Elements: elements, Pos: start}, nil
+}
+
+func (p *Parser) parseAtom() (ast.Node, error) {
+	tok := p.current()
+	p.pos++
+
+	switch tok.Kind {
+	case lexer.String:
+		value, err := unescapeString(tok.Literal[1 : len(tok.Literal)-1])
+		if err != nil {
+			return nil, &lexer.LispError{Pos: tok.Pos, Message: err.Error()}
+		}
+		return &ast.StringNode{Value: value, Pos: tok.Pos}, nil
+
+	case lexer.Number:
+		value, ok := number.Parse(tok.Literal)
+		if !ok {
+			return nil, &lexer.LispError{Pos: tok.Pos, Message: fmt.Sprintf("invalid number literal: %s", tok.Literal)}
+		}
+		return &ast.NumberNode{Value: value, Pos: tok.Pos}, nil
+
+	default:
+		return &ast.SymbolNode{Name: tok.Literal, Pos: tok.Pos}, nil
+	}
+}
+
+// unescapeString interprets the backslash escapes the lexer scanned
+// past but left untouched, turning a string token's raw contents (with
+// the surrounding quotes already stripped) into the literal string it
+// denotes.
+func unescapeString(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return "", fmt.Errorf("trailing backslash in string literal")
+		}
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case '"':
+			b.WriteByte('"')
+		case '\\':
+			b.WriteByte('\\')
+		default:
+			return "", fmt.Errorf("unknown escape sequence: \\%c", s[i])
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,138 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/ast"
+	"github.com/AOEIUVBPMFDTNL/golisp/lexer"
+)
+
+func parse(source string) (ast.Node, error) {
+	p, err := NewParser(source)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse()
+}
+
+func mustParse(t *testing.T, source string) ast.Node {
+	t.Helper()
+	node, err := parse(source)
+	if err != nil {
+		t.Fatalf("Parse(%q): unexpected error: %v", source, err)
+	}
+	return node
+}
+
+// A list whose head symbol is in ast.SpecialForms parses as a
+// SpecialFormNode, not an ordinary ListNode.
+func TestParseSpecialFormDispatch(t *testing.T) {
+	for form := range ast.SpecialForms {
+		source := "(" + form + ")"
+		node := mustParse(t, source)
+		sf, ok := node.(*ast.SpecialFormNode)
+		if !ok {
+			t.Errorf("Parse(%q) = %T, want *ast.SpecialFormNode", source, node)
+			continue
+		}
+		if sf.Form != form {
+			t.Errorf("Parse(%q).Form = %q, want %q", source, sf.Form, form)
+		}
+	}
+}
+
+// A list whose head symbol is not a special form parses as an ordinary
+// ListNode, even if it shares a name with a builtin procedure.
+func TestParseOrdinaryListIsNotSpecialForm(t *testing.T) {
+	node := mustParse(t, "(+ 1 2)")
+	if _, ok := node.(*ast.ListNode); !ok {
+		t.Fatalf("Parse(\"(+ 1 2)\") = %T, want *ast.ListNode", node)
+	}
+}
+
+func TestParseQuote(t *testing.T) {
+	node := mustParse(t, "'(1 2)")
+	q, ok := node.(*ast.QuoteNode)
+	if !ok {
+		t.Fatalf("Parse(\"'(1 2)\") = %T, want *ast.QuoteNode", node)
+	}
+	if _, ok := q.Node.(*ast.ListNode); !ok {
+		t.Fatalf("Parse(\"'(1 2)\").Node = %T, want *ast.ListNode", q.Node)
+	}
+}
+
+func TestParseStringUnescaping(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{`"hello"`, "hello"},
+		{`"a\nb"`, "a\nb"},
+		{`"a\tb"`, "a\tb"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`"back\\slash"`, `back\slash`},
+	}
+	for _, tt := range tests {
+		node := mustParse(t, tt.source)
+		s, ok := node.(*ast.StringNode)
+		if !ok {
+			t.Fatalf("Parse(%q) = %T, want *ast.StringNode", tt.source, node)
+		}
+		if s.Value != tt.want {
+			t.Errorf("Parse(%q).Value = %q, want %q", tt.source, s.Value, tt.want)
+		}
+	}
+}
+
+func TestParseUnknownEscapeIsAnError(t *testing.T) {
+	_, err := parse(`"bad \q"`)
+	if err == nil {
+		t.Fatal("Parse: want an error for an unknown escape sequence, got nil")
+	}
+}
+
+func TestParseMissingClosingParen(t *testing.T) {
+	_, err := parse("(+ 1 2")
+	if err == nil {
+		t.Fatal("Parse: want an error for a missing closing paren, got nil")
+	}
+}
+
+func TestParseUnexpectedClosingParen(t *testing.T) {
+	_, err := parse(")")
+	if err == nil {
+		t.Fatal("Parse: want an error for a stray closing paren, got nil")
+	}
+}
+
+func TestParseTrailingTokenIsAnError(t *testing.T) {
+	_, err := parse("1 2")
+	if err == nil {
+		t.Fatal("Parse: want an error when input remains after one top-level form, got nil")
+	}
+}
+
+func TestParseAllReadsEveryTopLevelForm(t *testing.T) {
+	nodes, err := ParseAll("1 2 (+ 1 2)")
+	if err != nil {
+		t.Fatalf("ParseAll: unexpected error: %v", err)
+	}
+	if len(nodes) != 3 {
+		t.Fatalf("ParseAll: got %d nodes, want 3", len(nodes))
+	}
+}
+
+// A NumberNode's position is the literal's own, not the enclosing
+// list's.
+func TestParsePositionsPointAtTheToken(t *testing.T) {
+	node := mustParse(t, "(+ 1 22)")
+	list, ok := node.(*ast.ListNode)
+	if !ok {
+		t.Fatalf("Parse: %T, want *ast.ListNode", node)
+	}
+	got := list.Elements[2].(*ast.NumberNode).Pos
+	want := lexer.Position{Line: 1, Column: 6, Offset: 5}
+	if got != want {
+		t.Errorf("22's position = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,23 @@
+package vm
+
+import (
+	"github.com/AOEIUVBPMFDTNL/golisp/compiler"
+	"github.com/AOEIUVBPMFDTNL/golisp/object"
+)
+
+// Frame is one call's worth of execution state: the closure being run,
+// the instruction pointer within it, and where its locals start on the
+// shared value stack.
+type Frame struct {
+	cl          *object.Closure
+	ip          int
+	basePointer int
+}
+
+func NewFrame(cl *object.Closure, basePointer int) *Frame {
+	return &Frame{cl: cl, ip: -1, basePointer: basePointer}
+}
+
+func (f *Frame) Instructions() compiler.Instructions {
+	return f.cl.Fn.Instructions
+}
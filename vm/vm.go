@@ -0,0 +1,361 @@
+// Package vm executes compiler.Bytecode on a stack machine, as a
+// faster alternative to the tree-walking evaluator.
+package vm
+
+import (
+	"fmt"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/compiler"
+	"github.com/AOEIUVBPMFDTNL/golisp/lexer"
+	"github.com/AOEIUVBPMFDTNL/golisp/number"
+	"github.com/AOEIUVBPMFDTNL/golisp/object"
+)
+
+const (
+	StackSize   = 2048
+	GlobalsSize = 65536
+	MaxFrames   = 1024
+)
+
+type VM struct {
+	constants []object.Value
+	globals   []object.Value
+
+	stack [StackSize]object.Value
+	sp    int
+
+	frames      [MaxFrames]*Frame
+	framesIndex int
+}
+
+func New(bytecode *compiler.Bytecode) *VM {
+	mainFn := &object.CompiledFunction{Instructions: bytecode.Instructions, SourceMap: bytecode.SourceMap}
+	mainClosure := &object.Closure{Fn: mainFn}
+	mainFrame := NewFrame(mainClosure, 0)
+
+	vm := &VM{
+		constants: bytecode.Constants,
+		globals:   make([]object.Value, GlobalsSize),
+	}
+	// object.StandardBuiltins are pre-defined in the compiler's global
+	// symbol table in the same order, so slot i here always matches
+	// whatever OpGetGlobal/OpSetGlobal index the compiler assigned name i.
+	for i, b := range object.StandardBuiltins() {
+		vm.globals[i] = b
+	}
+	vm.frames[0] = mainFrame
+	vm.framesIndex = 1
+	return vm
+}
+
+func (vm *VM) currentFrame() *Frame {
+	return vm.frames[vm.framesIndex-1]
+}
+
+func (vm *VM) pushFrame(f *Frame) error {
+	if vm.framesIndex >= MaxFrames {
+		return fmt.Errorf("stack overflow: exceeded max call depth of %d", MaxFrames)
+	}
+	vm.frames[vm.framesIndex] = f
+	vm.framesIndex++
+	return nil
+}
+
+func (vm *VM) popFrame() *Frame {
+	vm.framesIndex--
+	return vm.frames[vm.framesIndex]
+}
+
+// LastPoppedStackElem returns the value most recently popped off the
+// stack. Because Run leaves a trailing OpPop-free value for the final
+// top-level form, this is how callers retrieve the program's result.
+func (vm *VM) LastPoppedStackElem() object.Value {
+	return vm.stack[vm.sp]
+}
+
+func (vm *VM) push(v object.Value) error {
+	if vm.sp >= StackSize {
+		return fmt.Errorf("stack overflow")
+	}
+	vm.stack[vm.sp] = v
+	vm.sp++
+	return nil
+}
+
+func (vm *VM) pop() object.Value {
+	v := vm.stack[vm.sp-1]
+	vm.sp--
+	return v
+}
+
+// sourcePos looks up the position of the source form that compiled to
+// the instruction at ip in the current frame, by scanning its
+// CompiledFunction's SourceMap (built in increasing InstrPos order)
+// for the last entry at or before ip.
+func (vm *VM) sourcePos(ip int) (lexer.Position, bool) {
+	sourceMap := vm.currentFrame().cl.Fn.SourceMap
+	for i := len(sourceMap) - 1; i >= 0; i-- {
+		if sourceMap[i].InstrPos <= ip {
+			return sourceMap[i].SrcPos, true
+		}
+	}
+	return lexer.Position{}, false
+}
+
+// runtimeError wraps err as a LispError carrying the source position of
+// the instruction at ip, when one is known, the same way the evaluator
+// attaches a position to every error it returns.
+func (vm *VM) runtimeError(ip int, err error) error {
+	if pos, ok := vm.sourcePos(ip); ok {
+		return &lexer.LispError{Pos: pos, Message: err.Error()}
+	}
+	return err
+}
+
+func (vm *VM) Run() error {
+	for vm.currentFrame().ip < len(vm.currentFrame().Instructions())-1 {
+		vm.currentFrame().ip++
+		ip := vm.currentFrame().ip
+		ins := vm.currentFrame().Instructions()
+		op := compiler.Opcode(ins[ip])
+
+		switch op {
+		case compiler.OpConstant:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			if err := vm.push(vm.constants[idx]); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpPop:
+			vm.pop()
+
+		case compiler.OpAdd, compiler.OpSub, compiler.OpMul, compiler.OpDiv:
+			if err := vm.execBinaryArithmetic(op); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpEqual, compiler.OpGreaterThan, compiler.OpLessThan, compiler.OpGreaterEq, compiler.OpLessEq:
+			if err := vm.execComparison(op); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpJump:
+			pos := int(compiler.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip = pos - 1
+
+		case compiler.OpJumpFalse:
+			pos := int(compiler.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+			cond := vm.pop()
+			if !object.IsTruthy(cond) {
+				vm.currentFrame().ip = pos - 1
+			}
+
+		case compiler.OpGetGlobal:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			if err := vm.push(vm.globals[idx]); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpSetGlobal:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			vm.globals[idx] = vm.pop()
+
+		case compiler.OpGetLocal:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			frame := vm.currentFrame()
+			cell := vm.stack[frame.basePointer+int(idx)].(*object.Cell)
+			if err := vm.push(cell.Value); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpSetLocal:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			frame := vm.currentFrame()
+			cell := vm.stack[frame.basePointer+int(idx)].(*object.Cell)
+			cell.Value = vm.pop()
+
+		case compiler.OpGetFree:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			cell := vm.currentFrame().cl.Free[idx]
+			if err := vm.push(cell.Value); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpSetFree:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			vm.currentFrame().cl.Free[idx].Value = vm.pop()
+
+		case compiler.OpGetLocalRef:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			frame := vm.currentFrame()
+			cell := vm.stack[frame.basePointer+int(idx)].(*object.Cell)
+			if err := vm.push(cell); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpGetFreeRef:
+			idx := compiler.ReadUint16(ins[ip+1:])
+			vm.currentFrame().ip += 2
+			if err := vm.push(vm.currentFrame().cl.Free[idx]); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpClosure:
+			constIdx := compiler.ReadUint16(ins[ip+1:])
+			numFree := compiler.ReadUint8(ins[ip+3:])
+			vm.currentFrame().ip += 3
+
+			fn, ok := vm.constants[constIdx].(*object.CompiledFunction)
+			if !ok {
+				return vm.runtimeError(ip, fmt.Errorf("not a compiled function: %s", vm.constants[constIdx]))
+			}
+
+			// Each free variable is already a *Cell on the stack (every
+			// local lives behind one), so capturing it here shares the
+			// same mutable binding rather than snapshotting its value.
+			free := make([]*object.Cell, numFree)
+			for i := 0; i < int(numFree); i++ {
+				free[i] = vm.stack[vm.sp-int(numFree)+i].(*object.Cell)
+			}
+			vm.sp -= int(numFree)
+
+			if err := vm.push(&object.Closure{Fn: fn, Free: free}); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		case compiler.OpCall:
+			numArgs := int(compiler.ReadUint8(ins[ip+1:]))
+			vm.currentFrame().ip += 1
+
+			callee := vm.stack[vm.sp-1-numArgs]
+			switch callee := callee.(type) {
+			case *object.Closure:
+				if numArgs != callee.Fn.NumParams {
+					return vm.runtimeError(ip, fmt.Errorf("procedure expects %d argument(s), got %d", callee.Fn.NumParams, numArgs))
+				}
+				frame := NewFrame(callee, vm.sp-numArgs)
+				if frame.basePointer+callee.Fn.NumLocals > StackSize {
+					return vm.runtimeError(ip, fmt.Errorf("stack overflow"))
+				}
+				// Every local, including the ones a `define` inside the
+				// body will add beyond the parameters, lives behind its
+				// own Cell from the moment the frame exists. That way a
+				// nested lambda can capture one as a free variable (via
+				// OpGetLocalRef) before its OpSetLocal/`define` has run,
+				// and still see the value once it does.
+				for i := 0; i < numArgs; i++ {
+					vm.stack[frame.basePointer+i] = &object.Cell{Value: vm.stack[frame.basePointer+i]}
+				}
+				for i := numArgs; i < callee.Fn.NumLocals; i++ {
+					vm.stack[frame.basePointer+i] = &object.Cell{}
+				}
+				if err := vm.pushFrame(frame); err != nil {
+					return vm.runtimeError(ip, err)
+				}
+				vm.sp = frame.basePointer + callee.Fn.NumLocals
+
+			case *object.Builtin:
+				args := make([]object.Value, numArgs)
+				copy(args, vm.stack[vm.sp-numArgs:vm.sp])
+				result, err := callee.Fn(args)
+				if err != nil {
+					return vm.runtimeError(ip, err)
+				}
+				vm.sp = vm.sp - numArgs - 1
+				if err := vm.push(result); err != nil {
+					return vm.runtimeError(ip, err)
+				}
+
+			default:
+				return vm.runtimeError(ip, fmt.Errorf("not a procedure: %s", callee))
+			}
+
+		case compiler.OpReturn:
+			returnValue := vm.pop()
+			frame := vm.popFrame()
+			vm.sp = frame.basePointer - 1
+			if err := vm.push(returnValue); err != nil {
+				// The callee's frame is already popped, so look up the
+				// position at the call site in the now-current (caller)
+				// frame rather than the stale ip from the returning frame.
+				return vm.runtimeError(vm.currentFrame().ip, err)
+			}
+
+		case compiler.OpMakeList:
+			count := int(compiler.ReadUint16(ins[ip+1:]))
+			vm.currentFrame().ip += 2
+			list := make(object.ListVal, count)
+			copy(list, vm.stack[vm.sp-count:vm.sp])
+			vm.sp -= count
+			if err := vm.push(list); err != nil {
+				return vm.runtimeError(ip, err)
+			}
+
+		default:
+			return vm.runtimeError(ip, fmt.Errorf("unknown opcode: %d", op))
+		}
+	}
+
+	return nil
+}
+
+func (vm *VM) execBinaryArithmetic(op compiler.Opcode) error {
+	right, ok1 := vm.pop().(object.NumberVal)
+	left, ok2 := vm.pop().(object.NumberVal)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("arithmetic requires numbers")
+	}
+
+	var result object.NumberVal
+	var err error
+	switch op {
+	case compiler.OpAdd:
+		result = number.Add(left, right)
+	case compiler.OpSub:
+		result = number.Sub(left, right)
+	case compiler.OpMul:
+		result = number.Mul(left, right)
+	case compiler.OpDiv:
+		result, err = number.Div(left, right)
+	}
+	if err != nil {
+		return err
+	}
+	return vm.push(result)
+}
+
+func (vm *VM) execComparison(op compiler.Opcode) error {
+	right, ok1 := vm.pop().(object.NumberVal)
+	left, ok2 := vm.pop().(object.NumberVal)
+	if !ok1 || !ok2 {
+		return fmt.Errorf("comparison requires numbers")
+	}
+
+	cmp := number.Cmp(left, right)
+	var result bool
+	switch op {
+	case compiler.OpEqual:
+		result = cmp == 0
+	case compiler.OpGreaterThan:
+		result = cmp > 0
+	case compiler.OpLessThan:
+		result = cmp < 0
+	case compiler.OpGreaterEq:
+		result = cmp >= 0
+	case compiler.OpLessEq:
+		result = cmp <= 0
+	}
+	if result {
+		return vm.push(number.FromInt64(1))
+	}
+	return vm.push(number.FromInt64(0))
+}
@@ -0,0 +1,178 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/AOEIUVBPMFDTNL/golisp/compiler"
+	"github.com/AOEIUVBPMFDTNL/golisp/parser"
+)
+
+// run parses, compiles, and executes source the same way golisp.Run
+// does, but stays inside this package so these tests exercise the
+// compiler/vm pipeline directly rather than through the top-level
+// convenience wrapper.
+func run(t *testing.T, source string) string {
+	t.Helper()
+	p, err := parser.NewParser(source)
+	if err != nil {
+		t.Fatalf("NewParser(%q): unexpected error: %v", source, err)
+	}
+	node, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse(%q): unexpected error: %v", source, err)
+	}
+	bytecode, err := compiler.New().CompileProgram(node)
+	if err != nil {
+		t.Fatalf("CompileProgram(%q): unexpected error: %v", source, err)
+	}
+	machine := New(bytecode)
+	if err := machine.Run(); err != nil {
+		t.Fatalf("Run(%q): unexpected error: %v", source, err)
+	}
+	return machine.LastPoppedStackElem().String()
+}
+
+// runExpectingError is run's counterpart for cases where the error
+// itself, not a successful result, is what's under test.
+func runExpectingError(t *testing.T, source string) error {
+	t.Helper()
+	p, err := parser.NewParser(source)
+	if err != nil {
+		t.Fatalf("NewParser(%q): unexpected error: %v", source, err)
+	}
+	node, err := p.Parse()
+	if err != nil {
+		t.Fatalf("Parse(%q): unexpected error: %v", source, err)
+	}
+	bytecode, err := compiler.New().CompileProgram(node)
+	if err != nil {
+		t.Fatalf("CompileProgram(%q): unexpected error: %v", source, err)
+	}
+	return New(bytecode).Run()
+}
+
+// A variable captured by two closures created in the same enclosing
+// call is a single shared, mutable binding, not a snapshot taken at
+// each closure's creation: incrementing it through one closure is
+// visible to the other.
+func TestRunMutableClosureSharedAcrossCalls(t *testing.T) {
+	source := `((lambda ()
+		(define counter 0)
+		(define inc (lambda () (set! counter (+ counter 1)) counter))
+		(inc)
+		(inc)
+		(inc)))`
+	if got := run(t, source); got != "3" {
+		t.Errorf("run(%q) = %s, want 3", source, got)
+	}
+}
+
+// A lambda defined locally (not at the top level) and referencing its
+// own name recurses correctly: the inner lambda's capture of the outer
+// local must see the value `define` writes to it, even though the
+// capture happens before that write runs.
+func TestRunLocalRecursiveDefine(t *testing.T) {
+	source := `((lambda ()
+		(define fact (lambda (n) (if (= n 0) 1 (* n (fact (- n 1))))))
+		(fact 5)))`
+	if got := run(t, source); got != "120" {
+		t.Errorf("run(%q) = %s, want 120", source, got)
+	}
+}
+
+// Shadowing or redefining +, -, *, /, a comparison, or list must be
+// honored: the compiler's fast path for these names only applies when
+// the name still resolves to the original builtin, not by name alone.
+func TestRunShadowedBuiltinIsCalledNotInlined(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "global redefine of +",
+			source: `(begin (define + (lambda (a b) 42)) (+ 1 2))`,
+			want:   "42",
+		},
+		{
+			name:   "lambda parameter shadows list",
+			source: `((lambda (list) (list 1 2)) (lambda (a b) 99))`,
+			want:   "99",
+		},
+		{
+			name:   "lambda parameter shadows <",
+			source: `((lambda (<) (< 1 2)) (lambda (a b) 7))`,
+			want:   "7",
+		},
+	}
+	for _, tt := range tests {
+		if got := run(t, tt.source); got != tt.want {
+			t.Errorf("%s: run(%q) = %s, want %s", tt.name, tt.source, got, tt.want)
+		}
+	}
+}
+
+// An unshadowed builtin still takes the dedicated-opcode fast path and
+// behaves like the ordinary procedure it stands in for.
+func TestRunUnshadowedBuiltin(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"(+ 1 2 3)", "6"},
+		{"(- 10 4)", "6"},
+		{"(* 2 3 4)", "24"},
+		{"(< 1 2)", "1"},
+		{"(list 1 2 3)", "(1 2 3)"},
+	}
+	for _, tt := range tests {
+		if got := run(t, tt.source); got != tt.want {
+			t.Errorf("run(%q) = %s, want %s", tt.source, got, tt.want)
+		}
+	}
+}
+
+// Higher-order use of closures (returning a lambda from a lambda) still
+// works once free variables are captured as shared cells: each call to
+// the outer lambda produces an independent counter.
+func TestRunClosuresAreIndependentPerCall(t *testing.T) {
+	source := `(begin
+		(define make-counter (lambda ()
+			(define n 0)
+			(lambda () (set! n (+ n 1)) n)))
+		(define c1 (make-counter))
+		(define c2 (make-counter))
+		(c1)
+		(c1)
+		(c2)
+		(+ (c1) (c2)))`
+	if got := run(t, source); got != "5" {
+		t.Errorf("run(%q) = %s, want 5", source, got)
+	}
+}
+
+// A single call frame whose locals would push sp past the fixed-size
+// [StackSize]object.Value stack array reports a catchable stack
+// overflow error instead of panicking with an out-of-range index: the
+// OpCall closure branch pre-populates a Cell for every local, a write
+// that (unlike every other stack write, which goes through the
+// bounds-checked push) previously happened with no bounds check at
+// all.
+func TestRunLocalsOverflowingStackReturnsError(t *testing.T) {
+	var body strings.Builder
+	body.WriteString("((lambda ()")
+	for i := 0; i < StackSize+1; i++ {
+		fmt.Fprintf(&body, "(define v%d %d)", i, i)
+	}
+	body.WriteString("0))")
+
+	err := runExpectingError(t, body.String())
+	if err == nil {
+		t.Fatal("run: expected a stack overflow error, got nil")
+	}
+	if !strings.Contains(err.Error(), "stack overflow") {
+		t.Errorf("run: error = %v, want a stack overflow error", err)
+	}
+}